@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"boruvka/satellite"
+	"math"
+)
+
+// EuclideanMST computes the minimum spanning tree of points (ECI positions in km) under Euclidean distance
+// without building the complete graph. It runs Boruvka rounds in which every component finds its shortest
+// outgoing edge through nearest neighbour queries on a satellite.KDTree. The tree is labelled with the components
+// every round, so a query skips the subtrees lying entirely within its own component, and a query is bounded by
+// the shortest edge its component has found so far, so points deep inside a large component are rarely searched.
+// Links longer than maxRange km are not used, so the result is a minimum spanning forest when the points are
+// spread further apart; maxRange <= 0 means no limit.
+// The tree is returned in the format of Tree: keyed by the sorted node pair, valued by the pair and the weight,
+// which is the link length in metres as in GraphBuilderCatalog. Ties are broken by node ids, so the tree is unique.
+func EuclideanMST(points []satellite.Vector3, maxRange float64) map[[2]int][3]int {
+	tree := make(map[[2]int][3]int)
+	if maxRange <= 0 {
+		maxRange = math.Inf(1)
+	}
+	index := satellite.NewKDTree(points)
+
+	// union-find over the points, with path halving
+	parent := make([]int, len(points))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	// nearest point of another component for every point. Components only grow, so a cached neighbour that is
+	// still in another component is still the nearest one and needs no new query.
+	nearest := make([]satellite.Neighbor, len(points))
+	for i := range nearest {
+		nearest[i].Index = -1
+	}
+	done := make([]bool, len(points)) // no other component within maxRange, now or later
+	// lower bound of the distance to another component, left by a query that found nothing within its bound.
+	// It stays valid as components merge.
+	lower := make([]float64, len(points))
+	labels := make([]int, len(points))
+
+	for {
+		for i := range labels {
+			labels[i] = find(i)
+		}
+		components := index.Label(labels)
+
+		// shortest outgoing edge of every component, [point, neighbour] keyed by the component root
+		best := make(map[int][2]int)
+		for i := range points {
+			if done[i] {
+				continue
+			}
+			ci := find(i)
+			if nearest[i].Index < 0 || find(nearest[i].Index) == ci {
+				bound := maxRange
+				if b, ok := best[ci]; ok {
+					bound = math.Min(bound, emstDistance(points, b[0], b[1]))
+				}
+				if lower[i] >= bound { // cannot beat the edge the component already has
+					continue
+				}
+				found, ok := components.NearestOther(points[i], ci, bound)
+				if !ok {
+					if bound == maxRange {
+						done[i] = true
+					}
+					lower[i] = bound
+					nearest[i].Index = -1
+					continue
+				}
+				nearest[i] = found
+			}
+			if b, ok := best[ci]; !ok || emstLess(points, i, nearest[i].Index, b[0], b[1]) {
+				best[ci] = [2]int{i, nearest[i].Index}
+			}
+		}
+		if len(best) == 0 {
+			return tree
+		}
+
+		for _, e := range best {
+			a, b := find(e[0]), find(e[1])
+			if a == b { // both components picked the same edge
+				continue
+			}
+			parent[a] = b
+			n1, n2 := min(e[0], e[1]), max(e[0], e[1])
+			tree[[2]int{n1, n2}] = [3]int{n1, n2, int(math.Round(emstDistance(points, n1, n2) * 1000))}
+		}
+	}
+}
+
+// Orders the edges i1-j1 and i2-j2 by length, then by node ids
+func emstLess(points []satellite.Vector3, i1, j1, i2, j2 int) bool {
+	d1, d2 := emstDistance(points, i1, j1), emstDistance(points, i2, j2)
+	if d1 != d2 {
+		return d1 < d2
+	}
+	lo1, lo2 := min(i1, j1), min(i2, j2)
+	if lo1 != lo2 {
+		return lo1 < lo2
+	}
+	return max(i1, j1) < max(i2, j2)
+}
+
+func emstDistance(points []satellite.Vector3, i, j int) float64 {
+	p, q := points[i], points[j]
+	return math.Sqrt((p.X-q.X)*(p.X-q.X) + (p.Y-q.Y)*(p.Y-q.Y) + (p.Z-q.Z)*(p.Z-q.Z))
+}
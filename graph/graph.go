@@ -62,6 +62,13 @@ func (g *CGraph) DecNrNodes() {
 //AddEdgeBoth : adds edges in both directions, with same weight
 //Nodes are inserted in sorted order for faster comparison later
 func (g *CGraph) AddEdgeBoth(n1, n2 int, w int) {
+	g.addEdge(n1, n2, w)
+
+	fmt.Println(g)
+}
+
+//addEdge : AddEdgeBoth without printing the graph, for builders adding many edges
+func (g *CGraph) addEdge(n1, n2 int, w int) {
 	if n1 < n2 {
 		g.nodes[n1].edges[[2]int{n1, n2}] = [3]int{n1, n2, w}
 		g.nodes[n2].edges[[2]int{n1, n2}] = [3]int{n1, n2, w}
@@ -69,8 +76,6 @@ func (g *CGraph) AddEdgeBoth(n1, n2 int, w int) {
 		g.nodes[n1].edges[[2]int{n2, n1}] = [3]int{n2, n1, w}
 		g.nodes[n2].edges[[2]int{n2, n1}] = [3]int{n2, n1, w}
 	}
-
-	fmt.Println(g)
 }
 
 // Neighbors : returns a slice of node IDs that are linked to this node
@@ -1,12 +1,15 @@
 package graph
 
 import (
+	"boruvka/satellite"
 	"encoding/csv"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/tmc/dot"
 )
@@ -102,3 +105,71 @@ func GraphBuilderCsv(csvFilePath string) (c *CGraph, d *dot.Graph) {
 
 	return g, gdot
 }
+
+// GraphBuilderCatalog propagates every satellite of the catalog to t (updating the catalog in place) and builds
+// a graph with one node per satellite, node ids matching the catalog indices. Two satellites are linked when they
+// are at most maxRange km apart and the link stays minAlt km above the Earth (see satellite.LineOfSight).
+// Candidate pairs are found with a satellite.KDTree, so the cost grows with the number of links rather than with
+// the square of the catalog size.
+// Edge weights are the link lengths in metres.
+// Satellites that fail to propagate are kept as isolated nodes; the returned error then reports how many failed
+// and the first failure, but the graph is still usable.
+func GraphBuilderCatalog(cat satellite.Catalog, t time.Time, maxRange, minAlt float64) (c *CGraph, d *dot.Graph, err error) {
+	g := new(CGraph)
+	gdot := dot.NewGraph("Satellite Graph")
+	gdot.SetType(dot.GRAPH)
+	gdot.Set("layout", "neato")
+
+	valid := make([]bool, len(cat))
+	failed := 0
+	nodes := make([]*dot.Node, len(cat))
+	for i := range cat {
+		g.AddNode()
+		nodes[i] = dot.NewNode(fmt.Sprint(i))
+		nodes[i].Set("label", cat[i].Name)
+		gdot.AddNode(nodes[i])
+
+		if perr := cat[i].PropagateTo(t); perr != nil {
+			if err == nil {
+				err = fmt.Errorf("%s: %w", cat[i].Name, perr)
+			}
+			failed++
+			continue
+		}
+		valid[i] = true
+	}
+	if failed > 0 {
+		err = fmt.Errorf("%d of %d satellites failed to propagate, first %w", failed, len(cat), err)
+	}
+
+	// candidate links come from a range query on a k-d tree over the propagated positions, only those pass on to
+	// the much more expensive line of sight search
+	var index []int
+	var points []satellite.Vector3
+	for i := range cat {
+		if valid[i] {
+			index = append(index, i)
+			points = append(points, cat[i].Position)
+		}
+	}
+	tree := satellite.NewKDTree(points)
+	for a, i := range index {
+		for _, n := range tree.Range(points[a], maxRange) {
+			j := index[n.Index]
+			if j <= i {
+				continue
+			}
+			if visible, _ := satellite.LineOfSight(cat[i].Position, cat[j].Position, minAlt); !visible {
+				continue
+			}
+			w := int(math.Round(n.Distance * 1000))
+			g.addEdge(i, j, w)
+
+			e := dot.NewEdge(nodes[i], nodes[j])
+			e.Set("weight", fmt.Sprint(w))
+			gdot.AddEdge(e)
+		}
+	}
+
+	return g, gdot, err
+}
@@ -1,7 +1,12 @@
 package graph
 
 import (
+	"boruvka/satellite"
+	"fmt"
+	"math"
+	"math/rand"
 	"testing"
+	"time"
 )
 
 func TestAdd(t *testing.T) {
@@ -19,3 +24,182 @@ func TestAdd(t *testing.T) {
 	})
 
 }
+
+func TestGraphBuilderCatalog(t *testing.T) {
+	// three satellites in one 700 km circular orbit: 0 and 1 are 3 degrees apart, 2 is on the far side of the Earth
+	epoch := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	var cat satellite.Catalog
+	for i, anomaly := range []float64{0, 3, 180} {
+		el := satellite.TLEElements{SatNum: int64(90000 + i), Classification: "U", EpochYear: 2022, EpochDays: 152,
+			Inclination: 53, Eccentricity: 0, MeanAnomaly: anomaly, MeanMotion: 14.53, ElementNum: 999, RevNum: 1}
+		l1, l2, err := satellite.ElementsToTLE(el)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cat = append(cat, satellite.SimpleSatellite{Name: fmt.Sprint("SAT ", i), Ole1: l1, Ole2: l2})
+	}
+
+	g, gdot, err := GraphBuilderCatalog(cat, epoch, 5000, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.GetNrNodes() != 3 {
+		t.Errorf("Expected %d; but got %d", 3, g.GetNrNodes())
+	}
+	edges := g.EdgesAllMap()
+	if len(edges) != 1 {
+		t.Fatalf("Expected %d; but got %d", 1, len(edges))
+	}
+	// chord of 3 degrees at a radius of ~7080 km
+	w := edges[[2]int{0, 1}][2]
+	if w < 370000 || w > 372000 {
+		t.Errorf("Expected about %d; but got %d", 371000, w)
+	}
+	if cat[0].Time != epoch {
+		t.Errorf("Expected the catalog to be propagated to %v; but got %v", epoch, cat[0].Time)
+	}
+	if gdot == nil {
+		t.Errorf("Expected a dot graph")
+	}
+}
+
+func TestEuclideanMST(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	points := make([]satellite.Vector3, 300)
+	for i := range points {
+		// two clusters 20000 km apart
+		offset := 0.0
+		if i%2 == 1 {
+			offset = 20000
+		}
+		points[i] = satellite.Vector3{X: rng.Float64()*3000 + offset, Y: rng.Float64() * 3000, Z: rng.Float64() * 3000}
+	}
+	points[3] = points[1] // equal length edges must not create a cycle
+
+	// Prim's algorithm on the complete graph, per cluster when maxRange splits them
+	prim := func(maxRange float64) map[[2]int][3]int {
+		tree := make(map[[2]int][3]int)
+		in := make([]bool, len(points))
+		for root := range points {
+			if in[root] {
+				continue
+			}
+			in[root] = true
+			for {
+				bi, bj := -1, -1
+				for i := range points {
+					if !in[i] {
+						continue
+					}
+					for j := range points {
+						if in[j] || emstDistance(points, i, j) > maxRange {
+							continue
+						}
+						if bi < 0 || emstLess(points, i, j, bi, bj) {
+							bi, bj = i, j
+						}
+					}
+				}
+				if bi < 0 {
+					break
+				}
+				in[bj] = true
+				n1, n2 := min(bi, bj), max(bi, bj)
+				tree[[2]int{n1, n2}] = [3]int{n1, n2, int(math.Round(emstDistance(points, n1, n2) * 1000))}
+			}
+		}
+		return tree
+	}
+
+	cases := []struct {
+		name     string
+		maxRange float64
+		edges    int
+	}{
+		{"Tree", 0, len(points) - 1},
+		{"Forest", 10000, len(points) - 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := EuclideanMST(points, c.maxRange)
+			limit := c.maxRange
+			if limit <= 0 {
+				limit = math.Inf(1)
+			}
+			expected := prim(limit)
+			if len(got) != c.edges || len(expected) != c.edges {
+				t.Fatalf("Expected %d edges; but got %d (Prim %d)", c.edges, len(got), len(expected))
+			}
+			for k, v := range expected {
+				if got[k] != v {
+					t.Errorf("Expected %v; but got %v", v, got[k])
+				}
+			}
+		})
+	}
+}
+
+// Points spread over a LEO shell, as a constellation snapshot
+func shellPoints(n int, seed int64) []satellite.Vector3 {
+	rng := rand.New(rand.NewSource(seed))
+	points := make([]satellite.Vector3, n)
+	for i := range points {
+		z := rng.Float64()*2 - 1
+		phi := rng.Float64() * 2 * math.Pi
+		r := 6928 + rng.Float64()*50
+		points[i] = satellite.Vector3{X: r * math.Sqrt(1-z*z) * math.Cos(phi), Y: r * math.Sqrt(1-z*z) * math.Sin(phi), Z: r * z}
+	}
+	return points
+}
+
+func TestEuclideanMSTLarge(t *testing.T) {
+	points := shellPoints(3000, 11)
+
+	// Prim's algorithm in O(n^2) for the total length
+	expected := 0
+	dist := make([]float64, len(points))
+	in := make([]bool, len(points))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[0] = 0
+	for range points {
+		next := -1
+		for i := range points {
+			if !in[i] && (next < 0 || dist[i] < dist[next]) {
+				next = i
+			}
+		}
+		in[next] = true
+		expected += int(math.Round(dist[next] * 1000))
+		for i := range points {
+			if d := emstDistance(points, next, i); !in[i] && d < dist[i] {
+				dist[i] = d
+			}
+		}
+	}
+
+	tree := EuclideanMST(points, 0)
+	got := 0
+	for _, e := range tree {
+		got += e[2]
+	}
+	if len(tree) != len(points)-1 {
+		t.Errorf("Expected %d edges; but got %d", len(points)-1, len(tree))
+	}
+	// rounding to metres per edge may differ by a metre or so in total
+	if got < expected-len(points) || got > expected+len(points) {
+		t.Errorf("Expected %d; but got %d", expected, got)
+	}
+}
+
+func BenchmarkEuclideanMST(b *testing.B) {
+	for _, n := range []int{1000, 4000, 16000} {
+		points := shellPoints(n, 1)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				EuclideanMST(points, 0)
+			}
+		})
+	}
+}
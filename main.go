@@ -26,56 +26,157 @@ package main
 import (
 	"boruvka/graph"
 	"boruvka/satellite"
-	"bufio"
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-)
+	"regexp"
+	"sort"
+	"time"
 
-func parser() []satellite.SimpleSatellite {
+	"github.com/tmc/dot"
+)
 
-	var satlist = []satellite.SimpleSatellite{}
+func parser(path string) satellite.Catalog {
 
-	file, err := os.Open("satellite/SatDB.txt")
+	satlist, err := satellite.LoadCatalog(path)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer file.Close()
-
-	//graph to hold sats
-	//satG := new(graph.CGraph)
-
-	// TODO: Make this better, add scanner error checking, corrupt data read and filter, etc
-	scanner := bufio.NewScanner(file)
-	linecounter := 0
-	var sat satellite.SimpleSatellite
-	for scanner.Scan() {
-		s := scanner.Text()
-		if linecounter == 0 { // this line is the name of the satellite.
-			sat = satellite.SimpleSatellite{Name: s} // TODO: remove whitespace from name first
-		} else if linecounter == 1 { // this line is Line 1 of Orbit Info
-			sat = satellite.SimpleSatellite{Name: sat.Name, Ole1: s}
-		} else if linecounter == 2 { // this line is Line 2 of Orbit info && also final line of data, ready to append
-			sat = satellite.SimpleSatellite{Name: sat.Name, Ole1: sat.Ole1, Ole2: s}
-			satlist = append(satlist, sat)
-			//satG.AddNode() //need to pass name in here
-			linecounter = -1
-			sat = satellite.SimpleSatellite{}
-		}
-		linecounter++
-	}
-
-	// init all satellites
-	for n := range satlist {
-		satellite.InitSat(&satlist[n])
-	}
 	return satlist
 }
 
 func main() {
+	tlePath := flag.String("tle", "satellite/SatDB.txt", "TLE catalog the graph is built from")
+	walker := flag.String("walker", "", "build the graph from a synthetic Walker delta constellation i:T/P/F (e.g. 53:1584/72/17) with its epoch at -time instead of the TLE catalog")
+	walkerAlt := flag.Float64("walkeralt", 550, "altitude of the -walker constellation (km)")
+	walkerStar := flag.Bool("star", false, "use the Walker star pattern for -walker")
+	csvPath := flag.String("csv", "", "build the graph from this CSV file instead of the TLE catalog")
+	instant := flag.String("time", "2022-06-01T00:00:00Z", "instant (RFC 3339) the satellites are propagated to")
+	maxRange := flag.Float64("range", 1000, "maximum link range between satellites (km)")
+	minAlt := flag.Float64("minalt", 100, "minimum altitude of a link above the Earth (km)")
+	regime := flag.String("regime", "", "only use satellites in this orbit regime (LEO, MEO, GEO, HEO, BEYOND-GEO)")
+	name := flag.String("name", "", "only use satellites whose name matches this regular expression")
+	health := flag.Bool("health", false, "print the catalog health report for -time and exit")
+	emst := flag.Bool("emst", false, "print the Euclidean minimum spanning forest of the satellite positions (links up to -range, no line of sight check) and exit")
+	screen := flag.Duration("screen", 0, "print the close approaches (under -miss) within this duration from -time and exit")
+	miss := flag.Float64("miss", 5, "miss distance threshold of -screen (km)")
+	history := flag.String("history", "", "merge the -tle catalog into this TLE history file and use the element set of each satellite nearest to -time (not with -walker)")
+	maxAge := flag.Duration("maxage", 0, "exclude satellites with an epoch further than this from -time, duplicates and failures (0 keeps all, -health then uses 14 days)")
+	flag.Parse()
+	if *walker != "" && *history != "" {
+		// the synthetic element sets must not end up in the persistent history
+		log.Fatal("-walker cannot be combined with -history")
+	}
 
 	//########## Initialize graph ######################
-	g, gdot := graph.GraphBuilderCsv("data/graph02_12_nodes_no_BOM.csv")
+	var g *graph.CGraph
+	var gdot *dot.Graph
+	if *csvPath != "" {
+		g, gdot = graph.GraphBuilderCsv(*csvPath)
+	} else {
+		t, err := time.Parse(time.RFC3339, *instant)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var Satellites satellite.Catalog
+		if *walker != "" {
+			w, err := satellite.ParseWalker(*walker)
+			if err != nil {
+				log.Fatal(err)
+			}
+			w.Altitude, w.Epoch = *walkerAlt, t
+			if *walkerStar {
+				w.Pattern = satellite.WalkerStar
+			}
+			if Satellites, err = w.Catalog(); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			Satellites = parser(*tlePath)
+		}
+		if *history != "" {
+			h, err := satellite.LoadTLEHistory(*history)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if _, err := h.AddCatalog(Satellites); err != nil {
+				log.Println(err)
+			}
+			if err := h.Save(*history); err != nil {
+				log.Fatal(err)
+			}
+			Satellites = h.CatalogAt(t)
+		}
+		var filters []satellite.CatalogFilter
+		if *regime != "" {
+			r, err := satellite.ParseOrbitRegime(*regime)
+			if err != nil {
+				log.Fatal(err)
+			}
+			filters = append(filters, satellite.ByRegime(r))
+		}
+		if *name != "" {
+			pattern, err := regexp.Compile(*name)
+			if err != nil {
+				log.Fatal(err)
+			}
+			filters = append(filters, satellite.ByName(pattern))
+		}
+		if len(filters) > 0 {
+			Satellites = Satellites.Filter(filters...)
+		}
+		if *health || *maxAge > 0 {
+			report := satellite.CheckHealth(Satellites, t, satellite.HealthOptions{MaxEpochAge: *maxAge})
+			if *health {
+				if err := report.Write(os.Stdout); err != nil {
+					log.Fatal(err)
+				}
+				return
+			}
+			Satellites = report.Filter(Satellites)
+		}
+		if *screen > 0 {
+			conjunctions, err := satellite.ScreenConjunctions(context.Background(), Satellites, t, t.Add(*screen), satellite.ConjunctionOptions{Threshold: *miss})
+			if err != nil {
+				log.Println(err)
+			}
+			for _, c := range conjunctions {
+				fmt.Printf("%s  %-24s %-24s %8.3f km %7.3f km/s\n", c.TCA.Format(time.RFC3339Nano), Satellites[c.Primary].Name, Satellites[c.Secondary].Name, c.MissDistance, c.RelativeSpeed)
+			}
+			return
+		}
+		if *emst {
+			var ids []int
+			var points []satellite.Vector3
+			for i := range Satellites {
+				if err := Satellites[i].PropagateTo(t); err != nil {
+					continue
+				}
+				ids = append(ids, i)
+				points = append(points, Satellites[i].Position)
+			}
+			tree := graph.EuclideanMST(points, *maxRange)
+			edges := make([][3]int, 0, len(tree))
+			for _, v := range tree {
+				edges = append(edges, v)
+			}
+			sort.Slice(edges, func(a, b int) bool { return edges[a][2] < edges[b][2] })
+			total := 0
+			for _, v := range edges {
+				total += v[2]
+				fmt.Printf("%-24s %-24s %10.3f km\n", Satellites[ids[v[0]]].Name, Satellites[ids[v[1]]].Name, float64(v[2])/1000)
+			}
+			fmt.Printf("%d of %d satellites propagated, %d tree edges in %d components, %.3f km total\n", len(points), len(Satellites), len(tree), len(points)-len(tree), float64(total)/1000)
+			return
+		}
+		g, gdot, err = graph.GraphBuilderCatalog(Satellites, t, *maxRange, *minAlt)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
 	//generate dot file
 	file, err := os.Create("graph.dot")
 	if err != nil {
@@ -84,21 +185,6 @@ func main() {
 	defer file.Close()
 	file.WriteString(gdot.String())
 
-	Satellites := parser()
-
-	//fmt.Println(Satellites)
-
-	//generate dot file
-	f, err := os.Create("graph.dot")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer f.Close()
-
-	f.WriteString(gdot.String())
-
-	fmt.Println(Satellites)
-
 	g.Snapshot()
 
 	for g.GetNrNodes() > 1 {
@@ -116,14 +202,18 @@ func main() {
 				edge := g.NodeMinEdgeGet(id[1])
 				fmt.Println("node ", id[1], "--> minEdge:", edge)
 			}
-			//#######To do: If no min edge was found, this means isolated component
-			//#######-----REMOVE FROM THE GRAPH-----
 		}
 
 		//Edge Contraction is a multi-step process. It starts with a first pass
 		//that adds all minEdges to the Tree and fills up ContractionPairsSlice
 		g.BuildContractionPairsSlice()
 		fmt.Println("ContractionPairsSlice:", graph.ContractionPairsSlice)
+		//No component has an edge left: the graph is disconnected and the
+		//Tree holds a minimum spanning forest
+		if graph.LenContractionPairsSlice() == 0 {
+			fmt.Println(g.GetNrNodes(), "components left without edges, the tree is a spanning forest")
+			break
+		}
 		//Testing the tree (map)
 		fmt.Println("\tTree edges:")
 		fmt.Println(graph.Tree)
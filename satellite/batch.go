@@ -0,0 +1,171 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Options for the batch propagation functions
+type BatchOptions struct {
+	Workers    int  // number of goroutines propagating, defaults to runtime.NumCPU()
+	Velocities bool // also store velocities in an Ephemeris (tracks always carry them)
+}
+
+// Holds the propagated track of one catalog satellite over a batch time grid
+type SatTrack struct {
+	Index      int // index of the satellite in the catalog
+	Positions  []Vector3
+	Velocities []Vector3
+	Valid      int   // number of leading samples that propagated; samples from Valid on are zero
+	Err        error // propagation error of the first invalid sample, nil if all samples are valid
+}
+
+// Holds a catalog propagated over a time grid in compact columnar form. Sample j of satellite i is stored at
+// index i*len(Times)+j of each column. Values are stored as float32 (km and km/s), which keeps several thousand
+// objects over a day of 1 minute steps in memory at the cost of metre level rounding.
+type Ephemeris struct {
+	Times      []time.Time
+	X, Y, Z    []float32
+	VX, VY, VZ []float32 // nil unless BatchOptions.Velocities is set
+	Valid      []int     // per satellite, as in SatTrack
+	Errs       []error   // per satellite, as in SatTrack
+}
+
+// Returns the batch time grid: start, start+step, ... up to and including stop
+func BatchTimes(start, stop time.Time, step time.Duration) ([]time.Time, error) {
+	if step <= 0 {
+		return nil, errors.New("batch step must be positive")
+	}
+	if stop.Before(start) {
+		return nil, errors.New("batch stop is before start")
+	}
+	n := int(stop.Sub(start)/step) + 1
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * step)
+	}
+	return times, nil
+}
+
+// Propagates every satellite of the catalog from start to stop, streaming one SatTrack per satellite (in no
+// particular order) on the returned channel. The channel is closed once all tracks are sent or ctx is cancelled.
+func PropagateCatalog(ctx context.Context, cat Catalog, start, stop time.Time, step time.Duration, opts BatchOptions) (<-chan SatTrack, error) {
+	times, err := BatchTimes(start, stop, step)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan SatTrack, batchWorkers(opts))
+	go func() {
+		defer close(out)
+		runBatch(ctx, len(cat), batchWorkers(opts), func(i int) {
+			track := SatTrack{Index: i, Positions: make([]Vector3, len(times)), Velocities: make([]Vector3, len(times))}
+			track.Valid, track.Err = propagateTrack(ctx, cat[i].propagator(), times, func(j int, pos, vel Vector3) {
+				track.Positions[j] = pos
+				track.Velocities[j] = vel
+			})
+			select {
+			case out <- track:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return out, nil
+}
+
+// Propagates every satellite of the catalog from start to stop into a columnar Ephemeris.
+// Returns ctx.Err() if the context is cancelled before all satellites are done.
+func PropagateCatalogEphemeris(ctx context.Context, cat Catalog, start, stop time.Time, step time.Duration, opts BatchOptions) (*Ephemeris, error) {
+	times, err := BatchTimes(start, stop, step)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(cat) * len(times)
+	eph := &Ephemeris{
+		Times: times,
+		X:     make([]float32, n),
+		Y:     make([]float32, n),
+		Z:     make([]float32, n),
+		Valid: make([]int, len(cat)),
+		Errs:  make([]error, len(cat)),
+	}
+	if opts.Velocities {
+		eph.VX = make([]float32, n)
+		eph.VY = make([]float32, n)
+		eph.VZ = make([]float32, n)
+	}
+
+	// every worker writes a disjoint range of the columns, so no locking is needed
+	runBatch(ctx, len(cat), batchWorkers(opts), func(i int) {
+		base := i * len(times)
+		eph.Valid[i], eph.Errs[i] = propagateTrack(ctx, cat[i].propagator(), times, func(j int, pos, vel Vector3) {
+			eph.X[base+j], eph.Y[base+j], eph.Z[base+j] = float32(pos.X), float32(pos.Y), float32(pos.Z)
+			if opts.Velocities {
+				eph.VX[base+j], eph.VY[base+j], eph.VZ[base+j] = float32(vel.X), float32(vel.Y), float32(vel.Z)
+			}
+		})
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return eph, nil
+}
+
+// Returns the position of sample j of satellite i, and whether that sample is valid
+func (e *Ephemeris) Position(i, j int) (Vector3, bool) {
+	k := i*len(e.Times) + j
+	return Vector3{X: float64(e.X[k]), Y: float64(e.Y[k]), Z: float64(e.Z[k])}, j < e.Valid[i]
+}
+
+func batchWorkers(opts BatchOptions) int {
+	if opts.Workers > 0 {
+		return opts.Workers
+	}
+	return runtime.NumCPU()
+}
+
+// Runs work(i) for i in [0, n) on a pool of goroutines, stops handing out work once ctx is cancelled
+func runBatch(ctx context.Context, n, workers int, work func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				work(i)
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// Propagates one satellite over the time grid, handing each sample to store. Stops at the first propagation
+// error or when ctx is cancelled, and returns the number of samples stored.
+func propagateTrack(ctx context.Context, prop Propagator, times []time.Time, store func(j int, pos, vel Vector3)) (int, error) {
+	for j, t := range times {
+		if j%64 == 0 && ctx.Err() != nil {
+			return j, ctx.Err()
+		}
+		pos, vel, err := prop.Propagate(t)
+		if err != nil {
+			return j, err
+		}
+		store(j, pos, vel)
+	}
+	return len(times), nil
+}
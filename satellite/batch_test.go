@@ -0,0 +1,93 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+const batchTestCatalog = `CALSPHERE 1             
+1 00900U 64063C   22160.52204282  .00000408  00000+0  42495-3 0  9992
+2 00900  90.1760  40.7701 0029467  47.9267  23.7177 13.73809888869573
+CALSPHERE 2             
+1 00902U 64063E   22160.60603716  .00000042  00000+0  49695-4 0  9992
+2 00902  90.1900  43.8467 0018732 164.5602 223.6614 13.52716145657714
+
+1 01361U 65034C   22160.44307132  .00000004  00000+0 -61679-3 0  9990
+2 01361  32.1442  64.3055 0013473 209.7674 150.2057  9.89301420 64438
+`
+
+func TestReadCatalog(t *testing.T) {
+	cat, err := ReadCatalog(strings.NewReader(batchTestCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cat) != 3 {
+		t.Fatalf("Expected %d; but got %d", 3, len(cat))
+	}
+	if cat[0].Name != "CALSPHERE 1" {
+		t.Errorf("Expected %q; but got %q", "CALSPHERE 1", cat[0].Name)
+	}
+	// bare two line element sets are named by catalog number
+	if cat[2].Name != "01361" {
+		t.Errorf("Expected %q; but got %q", "01361", cat[2].Name)
+	}
+
+	if _, err := ReadCatalog(strings.NewReader("NAME\n1 00900U 64063C\n")); err == nil {
+		t.Errorf("Expected an error for an incomplete element set")
+	}
+}
+
+func TestPropagateCatalog(t *testing.T) {
+	cat, err := ReadCatalog(strings.NewReader(batchTestCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2022, 6, 10, 0, 0, 0, 0, time.UTC)
+	stop := start.Add(time.Hour)
+
+	t.Run("Streamed Tracks", func(t *testing.T) {
+		tracks, err := PropagateCatalog(context.Background(), cat, start, stop, time.Minute, BatchOptions{Workers: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := 0
+		for track := range tracks {
+			seen++
+			if track.Err != nil || track.Valid != 61 {
+				t.Fatalf("Expected 61 valid samples; but got %d (%v)", track.Valid, track.Err)
+			}
+			expected, _, _ := cat[track.Index].propagator().Propagate(start.Add(30 * time.Minute))
+			if track.Positions[30] != expected {
+				t.Errorf("Expected %v; but got %v", expected, track.Positions[30])
+			}
+		}
+		if seen != len(cat) {
+			t.Errorf("Expected %d; but got %d", len(cat), seen)
+		}
+	})
+	t.Run("Columnar Ephemeris", func(t *testing.T) {
+		eph, err := PropagateCatalogEphemeris(context.Background(), cat, start, stop, time.Minute, BatchOptions{Velocities: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _, _ := cat[1].propagator().Propagate(start.Add(45 * time.Minute))
+		got, ok := eph.Position(1, 45)
+		if !ok || math.Abs(got.X-expected.X) > 1e-3 || math.Abs(got.Y-expected.Y) > 1e-3 || math.Abs(got.Z-expected.Z) > 1e-3 {
+			t.Errorf("Expected %v; but got %v", expected, got)
+		}
+		if len(eph.VX) != len(cat)*61 {
+			t.Errorf("Expected %d; but got %d", len(cat)*61, len(eph.VX))
+		}
+	})
+	t.Run("Cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := PropagateCatalogEphemeris(ctx, cat, start, stop, time.Minute, BatchOptions{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected %v; but got %v", context.Canceled, err)
+		}
+	})
+}
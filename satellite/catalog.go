@@ -0,0 +1,70 @@
+package satellite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Catalog is an ordered list of satellites, typically read from a three line element file such as SatDB.txt
+type Catalog []SimpleSatellite
+
+// Reads a catalog from a TLE file, see ReadCatalog
+func LoadCatalog(path string) (Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCatalog(f)
+}
+
+// Reads a catalog of element sets. Records are either three lines (name, line 1, line 2) or bare two line
+// element sets, in which case the catalog number is used as the name. Blank lines are skipped.
+// The TLE fields themselves are not parsed until the satellite is initialized or propagated.
+func ReadCatalog(r io.Reader) (Catalog, error) {
+	var cat Catalog
+	var name string
+	var line1 string
+	lineNr := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNr++
+		s := strings.TrimRight(scanner.Text(), " \r")
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		switch {
+		case line1 == "" && strings.HasPrefix(s, "1 "):
+			line1 = s
+			if name == "" {
+				name = strings.TrimSpace(tleField(s, 2, 7))
+			}
+		case line1 != "" && strings.HasPrefix(s, "2 "):
+			cat = append(cat, SimpleSatellite{Name: name, Ole1: line1, Ole2: s})
+			name, line1 = "", ""
+		case line1 == "" && name == "":
+			name = strings.TrimSpace(strings.TrimPrefix(s, "0 "))
+		default:
+			return cat, fmt.Errorf("line %d: unexpected %q in element set of %q", lineNr, s, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return cat, err
+	}
+	if name != "" || line1 != "" {
+		return cat, fmt.Errorf("line %d: incomplete element set for %q", lineNr, name)
+	}
+	return cat, nil
+}
+
+// Returns the propagator of the satellite, parsing the TLE if InitSat has not been called. The satellite is not modified.
+func (s *SimpleSatellite) propagator() Propagator {
+	if s.prop != nil {
+		return s.prop
+	}
+	return NewSGP4Propagator(TLEToSat(s.Ole1, s.Ole2, GravityWGS84))
+}
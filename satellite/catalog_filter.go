@@ -0,0 +1,187 @@
+package satellite
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Orbit regime of a satellite, see OrbitSummary.Regime
+type OrbitRegime int
+
+const (
+	RegimeLEO       OrbitRegime = iota // low Earth orbit, apogee below 2000 km
+	RegimeMEO                          // medium Earth orbit, between LEO and geosynchronous
+	RegimeGEO                          // geosynchronous, period within 1300 to 1600 minutes
+	RegimeHEO                          // highly elliptical, eccentricity above 0.25
+	RegimeBeyondGEO                    // near circular beyond geosynchronous altitude
+)
+
+// Boundaries of the orbit regimes
+const (
+	leoMaxApogee float64       = 2000.0 // km
+	heoMinEcc    float64       = 0.25
+	geoMinPeriod time.Duration = 1300 * time.Minute
+	geoMaxPeriod time.Duration = 1600 * time.Minute
+)
+
+var regimeNames = [...]string{"LEO", "MEO", "GEO", "HEO", "BEYOND-GEO"}
+
+func (r OrbitRegime) String() string {
+	if r < 0 || int(r) >= len(regimeNames) {
+		return "unknown"
+	}
+	return regimeNames[r]
+}
+
+// Parses a regime name as printed by OrbitRegime.String, case insensitive
+func ParseOrbitRegime(name string) (OrbitRegime, error) {
+	for i, n := range regimeNames {
+		if strings.EqualFold(n, name) {
+			return OrbitRegime(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown orbit regime %q", name)
+}
+
+// Mean orbit of a catalog satellite, from its TLE or, without one, from the osculating elements of the
+// propagator at its epoch
+type OrbitSummary struct {
+	NoradID         int64 // zero for satellites without a TLE
+	Epoch           time.Time
+	Inclination     float64 // degrees
+	Eccentricity    float64
+	ApogeeAltitude  float64 // km
+	PerigeeAltitude float64 // km
+	Period          time.Duration
+}
+
+// Classifies the orbit by eccentricity, period and apogee altitude, in that order
+func (o OrbitSummary) Regime() OrbitRegime {
+	switch {
+	case o.Eccentricity > heoMinEcc:
+		return RegimeHEO
+	case o.Period >= geoMinPeriod && o.Period <= geoMaxPeriod:
+		return RegimeGEO
+	case o.Period > geoMaxPeriod:
+		return RegimeBeyondGEO
+	case o.ApogeeAltitude < leoMaxApogee:
+		return RegimeLEO
+	}
+	return RegimeMEO
+}
+
+// Returns the mean orbit of the satellite. Satellites without a TLE are propagated to their epoch.
+func (s *SimpleSatellite) Orbit() (OrbitSummary, error) {
+	prop := s.propagator()
+	if sgp4, ok := prop.(*SGP4Propagator); ok {
+		sat := sgp4.Sat
+		return OrbitSummary{
+			NoradID:         sat.NoradID(),
+			Epoch:           sat.Epoch(),
+			Inclination:     sat.Inclination(),
+			Eccentricity:    sat.Eccentricity(),
+			ApogeeAltitude:  sat.ApogeeAltitude(),
+			PerigeeAltitude: sat.PerigeeAltitude(),
+			Period:          sat.Period(),
+		}, nil
+	}
+
+	pos, vel, err := prop.Propagate(prop.Epoch())
+	if err != nil {
+		return OrbitSummary{}, err
+	}
+	el, err := StateToKepler(pos, vel, GravityWGS84)
+	if err != nil {
+		return OrbitSummary{}, err
+	}
+	return OrbitSummary{
+		Epoch:           prop.Epoch(),
+		Inclination:     el.Inclination * RAD2DEG,
+		Eccentricity:    el.Eccentricity,
+		ApogeeAltitude:  el.ApogeeAltitude(GravityWGS84),
+		PerigeeAltitude: el.PerigeeAltitude(GravityWGS84),
+		Period:          time.Duration(el.Period(GravityWGS84) * 1e9),
+	}, nil
+}
+
+// Decides whether a satellite passes a filter, given its mean orbit
+type CatalogFilter func(s *SimpleSatellite, orbit OrbitSummary) bool
+
+// Returns the satellites that pass all filters, in catalog order. Satellites whose orbit cannot be
+// determined (see SimpleSatellite.Orbit) never pass.
+func (c Catalog) Filter(filters ...CatalogFilter) Catalog {
+	var out Catalog
+	for i := range c {
+		orbit, err := c[i].Orbit()
+		if err != nil {
+			continue
+		}
+		keep := true
+		for _, f := range filters {
+			if !f(&c[i], orbit) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			out = append(out, c[i])
+		}
+	}
+	return out
+}
+
+// Keeps the satellites with one of the NORAD catalog numbers
+func ByNoradID(ids ...int64) CatalogFilter {
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return func(s *SimpleSatellite, orbit OrbitSummary) bool {
+		return set[orbit.NoradID]
+	}
+}
+
+// Keeps the satellites whose name matches the regular expression, e.g. regexp.MustCompile("^STARLINK")
+func ByName(pattern *regexp.Regexp) CatalogFilter {
+	return func(s *SimpleSatellite, orbit OrbitSummary) bool {
+		return pattern.MatchString(s.Name)
+	}
+}
+
+// Keeps the satellites in one of the orbit regimes
+func ByRegime(regimes ...OrbitRegime) CatalogFilter {
+	return func(s *SimpleSatellite, orbit OrbitSummary) bool {
+		regime := orbit.Regime()
+		for _, r := range regimes {
+			if r == regime {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Keeps the satellites with an inclination within [minDeg, maxDeg] degrees
+func ByInclination(minDeg, maxDeg float64) CatalogFilter {
+	return func(s *SimpleSatellite, orbit OrbitSummary) bool {
+		return orbit.Inclination >= minDeg && orbit.Inclination <= maxDeg
+	}
+}
+
+// Keeps the satellites whose whole orbit lies within [minKm, maxKm] of altitude: the perigee is at least
+// minKm and the apogee at most maxKm
+func ByAltitude(minKm, maxKm float64) CatalogFilter {
+	return func(s *SimpleSatellite, orbit OrbitSummary) bool {
+		return orbit.PerigeeAltitude >= minKm && orbit.ApogeeAltitude <= maxKm
+	}
+}
+
+// Keeps the satellites whose epoch is at most maxAge away from ref, in either direction
+func ByEpochAge(ref time.Time, maxAge time.Duration) CatalogFilter {
+	return func(s *SimpleSatellite, orbit OrbitSummary) bool {
+		age := ref.Sub(orbit.Epoch)
+		return age <= maxAge && age >= -maxAge
+	}
+}
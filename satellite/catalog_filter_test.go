@@ -0,0 +1,89 @@
+package satellite
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCatalogFilter(t *testing.T) {
+	cat, err := LoadCatalog("SatDB.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Regimes", func(t *testing.T) {
+		expected := map[string]OrbitRegime{"ISS (ZARYA)": RegimeLEO, "INTELSAT 901 (IS-901)": RegimeGEO, "CALSPHERE 1": RegimeLEO, "NAVSTAR 43 (USA 132)": RegimeMEO}
+		found := 0
+		for i := range cat {
+			regime, ok := expected[cat[i].Name]
+			if !ok {
+				continue
+			}
+			found++
+			orbit, err := cat[i].Orbit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if orbit.Regime() != regime {
+				t.Errorf("%s: Expected %v; but got %v", cat[i].Name, regime, orbit.Regime())
+			}
+		}
+		if found != len(expected) {
+			t.Errorf("Expected %d; but got %d", len(expected), found)
+		}
+		for _, r := range []OrbitRegime{RegimeLEO, RegimeMEO, RegimeGEO, RegimeHEO} {
+			if len(cat.Filter(ByRegime(r))) == 0 {
+				t.Errorf("Expected %v satellites in SatDB.txt", r)
+			}
+		}
+	})
+	t.Run("Name and id", func(t *testing.T) {
+		calsphere := cat.Filter(ByName(regexp.MustCompile("^CALSPHERE")))
+		if len(calsphere) < 2 || calsphere[0].Name != "CALSPHERE 1" {
+			t.Errorf("Expected the CALSPHERE satellites; but got %d", len(calsphere))
+		}
+		byID := cat.Filter(ByNoradID(900, 902))
+		if len(byID) != 2 || byID[1].Name != "CALSPHERE 2" {
+			t.Errorf("Expected CALSPHERE 1 and 2; but got %v", byID)
+		}
+	})
+	t.Run("Shell", func(t *testing.T) {
+		shell := cat.Filter(ByRegime(RegimeLEO), ByAltitude(540, 560), ByInclination(53, 53.3))
+		if len(shell) == 0 {
+			t.Fatal("Expected satellites in the 550 km, 53 degree shell")
+		}
+		for i := range shell {
+			orbit, _ := shell[i].Orbit()
+			if orbit.PerigeeAltitude < 540 || orbit.ApogeeAltitude > 560 || orbit.Inclination < 53 || orbit.Inclination > 53.3 {
+				t.Errorf("%s: unexpected orbit %+v", shell[i].Name, orbit)
+			}
+		}
+	})
+	t.Run("Epoch age", func(t *testing.T) {
+		ref := time.Date(2022, 6, 10, 0, 0, 0, 0, time.UTC)
+		fresh := cat.Filter(ByEpochAge(ref, 2*24*time.Hour))
+		if len(fresh) == 0 || len(fresh) == len(cat) {
+			t.Errorf("Expected part of the catalog; but got %d of %d", len(fresh), len(cat))
+		}
+	})
+	t.Run("Numerical", func(t *testing.T) {
+		epoch := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+		prop, err := NewNumericalPropagatorFromKepler(epoch, KeplerElements{SemiMajorAxis: 26560, Eccentricity: 0.7, Inclination: 63.4 * DEG2RAD}, NumericalOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		synthetic := Catalog{NewSimpleSatellite("MOLNIYA-LIKE", prop)}
+		if len(synthetic.Filter(ByRegime(RegimeHEO), ByInclination(63, 64))) != 1 {
+			t.Errorf("Expected the synthetic satellite to be HEO")
+		}
+	})
+	t.Run("Parse regime", func(t *testing.T) {
+		if r, err := ParseOrbitRegime("geo"); err != nil || r != RegimeGEO {
+			t.Errorf("Expected %v; but got %v (%v)", RegimeGEO, r, err)
+		}
+		if _, err := ParseOrbitRegime("SSO"); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
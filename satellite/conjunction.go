@@ -0,0 +1,282 @@
+package satellite
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Options for ScreenConjunctions, zero values select the defaults
+type ConjunctionOptions struct {
+	Threshold  float64       // miss distance (km) below which an approach is reported, defaults to 5
+	Step       time.Duration // step of the coarse screening, defaults to 1 minute
+	BandMargin float64       // widens the perigee to apogee band of every object (km), defaults to 30
+	Workers    int           // goroutines propagating each step, defaults to runtime.NumCPU()
+}
+
+// A close approach between two catalog objects
+type Conjunction struct {
+	Primary, Secondary int       // catalog indices, Primary < Secondary
+	TCA                time.Time // time of closest approach
+	MissDistance       float64   // km
+	RelativeSpeed      float64   // km/s at TCA
+}
+
+// Screens the catalog for close approaches between start and stop and returns those with a miss distance below
+// the threshold, closest first.
+// The screening runs in three stages:
+//  1. apogee/perigee prefilter: pairs whose altitude bands (widened by BandMargin to cover the difference between
+//     mean and osculating altitudes) do not overlap can never meet, objects that overlap nobody are not propagated
+//  2. coarse screening: every Step all objects are propagated and hashed into a spatial grid whose cells are as
+//     large as the distance any pair can close within half a step. Neighbouring pairs whose linearly extrapolated
+//     relative motion passes within the threshold plus a curvature bound are flagged
+//  3. refinement: the time of closest approach of a flagged pair is the root of the range rate (the relative
+//     position dotted with the relative velocity) within a step on either side, bisected to passPrecision.
+//     Approaches of one pair found from neighbouring steps are merged. Approaches in progress at start or stop are
+//     clipped to the window, as in FindPasses.
+//
+// Objects that fail to propagate are left out from the failing step on; the approaches found are then returned
+// with an error reporting the number of failed objects and the first failure.
+func ScreenConjunctions(ctx context.Context, cat Catalog, start, stop time.Time, opts ConjunctionOptions) ([]Conjunction, error) {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 5
+	}
+	if opts.Step <= 0 {
+		opts.Step = time.Minute
+	}
+	if opts.BandMargin <= 0 {
+		opts.BandMargin = 30
+	}
+	times, err := BatchTimes(start, stop, opts.Step)
+	if err != nil {
+		return nil, err
+	}
+	if times[len(times)-1].Before(stop) {
+		times = append(times, stop)
+	}
+
+	failures := make([]error, len(cat))
+	perigee := make([]float64, len(cat))
+	apogee := make([]float64, len(cat))
+	for i := range cat {
+		orbit, err := cat[i].Orbit()
+		if err != nil {
+			failures[i] = err
+			continue
+		}
+		perigee[i] = orbit.PerigeeAltitude - opts.BandMargin
+		apogee[i] = orbit.ApogeeAltitude + opts.BandMargin
+	}
+	overlap := func(i, j int) bool { return perigee[i] <= apogee[j] && perigee[j] <= apogee[i] }
+
+	// sweep over the objects sorted by perigee to find those whose band overlaps another band
+	order := make([]int, 0, len(cat))
+	for i := range cat {
+		if failures[i] == nil {
+			order = append(order, i)
+		}
+	}
+	sort.Slice(order, func(a, b int) bool { return perigee[order[a]] < perigee[order[b]] })
+	screened := make([]bool, len(cat))
+	for a, i := range order {
+		for _, j := range order[a+1:] {
+			if perigee[j] > apogee[i] {
+				break
+			}
+			screened[i], screened[j] = true, true
+		}
+	}
+
+	props := make([]Propagator, len(cat))
+	var active []int
+	for i := range cat {
+		if screened[i] {
+			props[i] = cat[i].propagator()
+			active = append(active, i)
+		}
+	}
+	// position and velocity of j relative to i
+	relative := func(i, j int, t time.Time) (Vector3, Vector3, error) {
+		ri, vi, err := props[i].Propagate(t)
+		if err != nil {
+			return Vector3{}, Vector3{}, err
+		}
+		rj, vj, err := props[j].Propagate(t)
+		if err != nil {
+			return Vector3{}, Vector3{}, err
+		}
+		return Vector3{X: rj.X - ri.X, Y: rj.Y - ri.Y, Z: rj.Z - ri.Z}, Vector3{X: vj.X - vi.X, Y: vj.Y - vi.Y, Z: vj.Z - vi.Z}, nil
+	}
+
+	half := opts.Step.Seconds() / 2
+	pos := make([]Vector3, len(cat))
+	vel := make([]Vector3, len(cat))
+	found := make(map[[2]int][]Conjunction)
+	for _, tk := range times {
+		runBatch(ctx, len(active), batchWorkers(BatchOptions{Workers: opts.Workers}), func(a int) {
+			i := active[a]
+			pos[i], vel[i], failures[i] = props[i].Propagate(tk)
+		})
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// drop the objects that failed and bound the relative motion of the rest within half a step
+		vmax, rmin := 0.0, math.Inf(1)
+		kept := active[:0]
+		for _, i := range active {
+			if failures[i] != nil {
+				continue
+			}
+			kept = append(kept, i)
+			vmax = math.Max(vmax, vecNorm(vel[i]))
+			rmin = math.Min(rmin, vecNorm(pos[i]))
+		}
+		active = kept
+		if len(active) < 2 {
+			break
+		}
+		cell, curvature := conjunctionCell(opts.Threshold, opts.Step, vmax, rmin)
+
+		grid := make(map[[3]int][]int, len(active))
+		key := func(p Vector3) [3]int {
+			return [3]int{int(math.Floor(p.X / cell)), int(math.Floor(p.Y / cell)), int(math.Floor(p.Z / cell))}
+		}
+		for _, i := range active {
+			k := key(pos[i])
+			grid[k] = append(grid[k], i)
+		}
+
+		for _, i := range active {
+			k := key(pos[i])
+			for dx := -1; dx <= 1; dx++ {
+				for dy := -1; dy <= 1; dy++ {
+					for dz := -1; dz <= 1; dz++ {
+						for _, j := range grid[[3]int{k[0] + dx, k[1] + dy, k[2] + dz}] {
+							if j <= i || !overlap(i, j) {
+								continue
+							}
+							r := Vector3{X: pos[j].X - pos[i].X, Y: pos[j].Y - pos[i].Y, Z: pos[j].Z - pos[i].Z}
+							v := Vector3{X: vel[j].X - vel[i].X, Y: vel[j].Y - vel[i].Y, Z: vel[j].Z - vel[i].Z}
+							tmin := 0.0
+							if vv := vecDot(v, v); vv > 0 {
+								tmin = math.Max(-half, math.Min(half, -vecDot(r, v)/vv))
+							}
+							if vecNorm(Vector3{X: r.X + v.X*tmin, Y: r.Y + v.Y*tmin, Z: r.Z + v.Z*tmin}) >= opts.Threshold+curvature {
+								continue
+							}
+
+							t0, t1 := tk.Add(-opts.Step), tk.Add(opts.Step)
+							if t0.Before(start) {
+								t0 = start
+							}
+							if t1.After(stop) {
+								t1 = stop
+							}
+							tca, ok := conjunctionTCA(func(t time.Time) (float64, error) {
+								r, v, err := relative(i, j, t)
+								return vecDot(r, v), err
+							}, t0, t1, t0.Equal(start), t1.Equal(stop))
+							if !ok {
+								continue
+							}
+							r, v, err := relative(i, j, tca)
+							if err != nil || vecNorm(r) >= opts.Threshold {
+								continue
+							}
+							found[[2]int{i, j}] = append(found[[2]int{i, j}], Conjunction{Primary: i, Secondary: j, TCA: tca, MissDistance: vecNorm(r), RelativeSpeed: vecNorm(v)})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var conjunctions []Conjunction
+	for _, list := range found {
+		conjunctions = append(conjunctions, mergeConjunctions(list, opts.Step)...)
+	}
+	sort.Slice(conjunctions, func(a, b int) bool {
+		if conjunctions[a].MissDistance != conjunctions[b].MissDistance {
+			return conjunctions[a].MissDistance < conjunctions[b].MissDistance
+		}
+		return conjunctions[a].TCA.Before(conjunctions[b].TCA)
+	})
+
+	failed, first := 0, -1
+	for i, err := range failures {
+		if err != nil {
+			failed++
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+	if failed > 0 {
+		return conjunctions, fmt.Errorf("%d of %d objects failed to propagate, first %s: %w", failed, len(cat), cat[first].Name, failures[first])
+	}
+	return conjunctions, nil
+}
+
+// Returns the edge (km) of the grid cells of a screening step and the curvature bound (km): how far the relative
+// motion of two objects at a radius of at least rmin km deviates from a straight line within half a step. The
+// cell covers the threshold, the curvature and the distance two objects of at most vmax km/s close in half a step.
+func conjunctionCell(threshold float64, step time.Duration, vmax, rmin float64) (cell, curvature float64) {
+	half := step.Seconds() / 2
+	curvature = 0.5 * (2.0 * getGravConst(GravityWGS84).mu / (rmin * rmin)) * half * half
+	return threshold + curvature + 2.0*vmax*half, curvature
+}
+
+// Returns the time of closest approach between t0 and t1: the root of rangeRate where it changes from approaching
+// (negative) to receding, bisected to passPrecision. Without such a root the closest approach lies on an edge,
+// which is only returned where the edge is clipped to the screening window (atStart, atStop): an inner edge
+// belongs to the encounter of a neighbouring step. ok is false if there is none or rangeRate fails.
+func conjunctionTCA(rangeRate func(time.Time) (float64, error), t0, t1 time.Time, atStart, atStop bool) (tca time.Time, ok bool) {
+	f0, err := rangeRate(t0)
+	if err != nil {
+		return time.Time{}, false
+	}
+	f1, err := rangeRate(t1)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch {
+	case f0 < 0 && f1 > 0:
+		for t1.Sub(t0) > passPrecision {
+			mid := t0.Add(t1.Sub(t0) / 2)
+			f, err := rangeRate(mid)
+			if err != nil {
+				return time.Time{}, false
+			}
+			if f < 0 {
+				t0 = mid
+			} else {
+				t1 = mid
+			}
+		}
+		return t0.Add(t1.Sub(t0) / 2), true
+	case f0 >= 0 && atStart:
+		return t0, true
+	case f1 <= 0 && atStop:
+		return t1, true
+	}
+	return time.Time{}, false
+}
+
+// Merges the approaches of one pair whose TCAs are no more than a step apart (the same encounter found from
+// neighbouring steps), keeping the closest
+func mergeConjunctions(list []Conjunction, step time.Duration) []Conjunction {
+	sort.Slice(list, func(a, b int) bool { return list[a].TCA.Before(list[b].TCA) })
+	merged := list[:1]
+	for _, c := range list[1:] {
+		last := &merged[len(merged)-1]
+		if c.TCA.Sub(last.TCA) > step {
+			merged = append(merged, c)
+		} else if c.MissDistance < last.MissDistance {
+			*last = c
+		}
+	}
+	return merged
+}
@@ -0,0 +1,158 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScreenConjunctions(t *testing.T) {
+	epoch := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	// two circular orbits crossing over the x axis 15 minutes after the epoch, 2 km apart in radius
+	lead := func(a float64) float64 { return TWOPI - math.Sqrt(getGravConst(GravityWGS84).mu/(a*a*a))*900 }
+	newSat := func(name string, el KeplerElements) SimpleSatellite {
+		prop, err := NewNumericalPropagatorFromKepler(epoch, el, NumericalOptions{Zonals: 0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewSimpleSatellite(name, prop)
+	}
+	cat := Catalog{
+		newSat("EQUATORIAL", KeplerElements{SemiMajorAxis: 7000, TrueAnomaly: lead(7000)}),
+		newSat("GEO", KeplerElements{SemiMajorAxis: 42164, TrueAnomaly: 0}),
+		newSat("POLAR", KeplerElements{SemiMajorAxis: 7002, Inclination: math.Pi / 2, TrueAnomaly: lead(7002)}),
+	}
+
+	conjunctions, err := ScreenConjunctions(context.Background(), cat, epoch, epoch.Add(time.Hour), ConjunctionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conjunctions) != 1 {
+		t.Fatalf("Expected 1 conjunction; but got %v", conjunctions)
+	}
+	c := conjunctions[0]
+	if c.Primary != 0 || c.Secondary != 2 {
+		t.Errorf("Expected the pair 0, 2; but got %d, %d", c.Primary, c.Secondary)
+	}
+	if dt := c.TCA.Sub(epoch.Add(15 * time.Minute)); dt < -time.Second || dt > time.Second {
+		t.Errorf("Expected the TCA about 15 min after the epoch; but got %v", c.TCA)
+	}
+	if math.Abs(c.MissDistance-2) > 1e-3 {
+		t.Errorf("Expected %f; but got %f", 2.0, c.MissDistance)
+	}
+	if math.Abs(c.RelativeSpeed-math.Sqrt2*7.546) > 0.01 {
+		t.Errorf("Expected %f; but got %f", math.Sqrt2*7.546, c.RelativeSpeed)
+	}
+	for _, dt := range []time.Duration{-time.Second, time.Second} {
+		if d := conjunctionDistance(cat, c.Primary, c.Secondary, c.TCA.Add(dt)); d <= c.MissDistance {
+			t.Errorf("Expected the distance %v from TCA to exceed %f; but got %f", dt, c.MissDistance, d)
+		}
+	}
+
+	t.Run("Threshold", func(t *testing.T) {
+		conjunctions, err := ScreenConjunctions(context.Background(), cat, epoch, epoch.Add(time.Hour), ConjunctionOptions{Threshold: 1})
+		if err != nil || len(conjunctions) != 0 {
+			t.Errorf("Expected no conjunction below 1 km; but got %v, %v", conjunctions, err)
+		}
+	})
+	t.Run("Cell boundary", func(t *testing.T) {
+		// a threshold whose grid puts a cell boundary between the pair (x = 7000 and 7002 km) at the TCA step
+		_, curvature := conjunctionCell(0, time.Minute, math.Sqrt(getGravConst(GravityWGS84).mu/7000), 7000)
+		threshold := 7001.0/15 - curvature - 60*math.Sqrt(getGravConst(GravityWGS84).mu/7000)
+		cell, _ := conjunctionCell(threshold, time.Minute, math.Sqrt(getGravConst(GravityWGS84).mu/7000), 7000)
+		if math.Floor(7000/cell) == math.Floor(7002/cell) {
+			t.Fatalf("Expected a cell boundary between 7000 and 7002 km; but got cells of %f km", cell)
+		}
+		conjunctions, err := ScreenConjunctions(context.Background(), cat, epoch, epoch.Add(time.Hour), ConjunctionOptions{Threshold: threshold})
+		if err != nil || len(conjunctions) != 1 || !conjunctions[0].TCA.Equal(c.TCA) {
+			t.Errorf("Expected %v; but got %v, %v", c, conjunctions, err)
+		}
+	})
+	t.Run("Clipped", func(t *testing.T) {
+		// windows starting just after and ending just before the TCA report the approach at their edge
+		for _, w := range [][2]time.Time{{c.TCA.Add(200 * time.Millisecond), epoch.Add(time.Hour)}, {epoch, c.TCA.Add(-200 * time.Millisecond)}} {
+			edge := w[0]
+			if w[1].Before(c.TCA) {
+				edge = w[1]
+			}
+			conjunctions, err := ScreenConjunctions(context.Background(), cat, w[0], w[1], ConjunctionOptions{})
+			if err != nil || len(conjunctions) != 1 || !conjunctions[0].TCA.Equal(edge) {
+				t.Fatalf("Expected a conjunction at %v; but got %v, %v", edge, conjunctions, err)
+			}
+			if d := conjunctionDistance(cat, 0, 2, edge); math.Abs(conjunctions[0].MissDistance-d) > 1e-9 {
+				t.Errorf("Expected %f; but got %f", d, conjunctions[0].MissDistance)
+			}
+		}
+	})
+}
+
+func TestScreenConjunctionsTLE(t *testing.T) {
+	// two circular orbits of 51.6 degrees inclination whose nodes are 60 degrees apart, phased to meet where the
+	// planes cross in the north once per orbit (in the south they pass 15 km apart)
+	epoch := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	cat := Catalog{
+		{Name: "NORTH 1", Ole1: "1 90001U          22152.00000000  .00000000  00000+0  00000+0 0  9990",
+			Ole2: "2 90001  51.6000   0.0000 0000000   0.0000  51.6038 15.50000000    19"},
+		{Name: "NORTH 2", Ole1: "1 90002U          22152.00000000  .00000000  00000+0  00000+0 0  9991",
+			Ole2: "2 90002  51.6000  60.0000 0000000   0.0000  12.1962 15.50000000    14"},
+	}
+	conjunctions, err := ScreenConjunctions(context.Background(), cat, epoch, epoch.Add(2*time.Hour), ConjunctionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conjunctions) != 2 {
+		t.Fatalf("Expected 2 conjunctions; but got %v", conjunctions)
+	}
+	if conjunctions[0].TCA.After(conjunctions[1].TCA) {
+		conjunctions[0], conjunctions[1] = conjunctions[1], conjunctions[0]
+	}
+	meanMotion := 15.5
+	period := time.Duration(1440 / meanMotion * float64(time.Minute))
+	for i, expected := range []time.Duration{15 * time.Minute, 15*time.Minute + period} {
+		c := conjunctions[i]
+		if dt := c.TCA.Sub(epoch.Add(expected)); dt < -5*time.Second || dt > 5*time.Second {
+			t.Errorf("Expected the TCA about %v after the epoch; but got %v", expected, c.TCA)
+		}
+		// the closest approach of a millisecond scan around the TCA
+		best, miss := c.TCA, math.Inf(1)
+		for tm := c.TCA.Add(-time.Second); tm.Before(c.TCA.Add(time.Second)); tm = tm.Add(time.Millisecond) {
+			if d := conjunctionDistance(cat, 0, 1, tm); d < miss {
+				best, miss = tm, d
+			}
+		}
+		if dt := c.TCA.Sub(best); dt < -2*time.Millisecond || dt > 2*time.Millisecond {
+			t.Errorf("Expected %v; but got %v", best, c.TCA)
+		}
+		if math.Abs(c.MissDistance-miss) > 1e-2 || miss > 1 {
+			t.Errorf("Expected %f; but got %f", miss, c.MissDistance)
+		}
+		if math.Abs(c.RelativeSpeed-6.0) > 0.05 {
+			t.Errorf("Expected %f; but got %f", 6.0, c.RelativeSpeed)
+		}
+	}
+}
+
+func TestScreenConjunctionsFailure(t *testing.T) {
+	// two low objects with an extreme drag term, which decay within the day
+	cat := Catalog{
+		{Name: "DECAY 1", Ole1: "1 99999U          22100.50000000  .00000000  00000+0  50000-1 0    03",
+			Ole2: "2 99999  51.6000   0.0000 0005000   0.0000   0.0000 16.00000000    01"},
+		{Name: "DECAY 2", Ole1: "1 99999U          22100.50000000  .00000000  00000+0  50000-1 0    03",
+			Ole2: "2 99999  51.6000  90.0000 0005000   0.0000   0.0000 16.00000000    00"},
+	}
+	start := time.Date(2022, 4, 10, 12, 0, 0, 0, time.UTC)
+	_, err := ScreenConjunctions(context.Background(), cat, start, start.Add(24*time.Hour), ConjunctionOptions{Step: 10 * time.Minute})
+	if !errors.Is(err, ErrDecayed) || !strings.Contains(err.Error(), "2 of 2 objects failed to propagate, first DECAY 1") {
+		t.Errorf("Expected a decay of both objects; but got %v", err)
+	}
+}
+
+// Distance between two catalog objects at tm (km)
+func conjunctionDistance(cat Catalog, i, j int, tm time.Time) float64 {
+	ri, _, _ := cat[i].propagator().Propagate(tm)
+	rj, _, _ := cat[j].propagator().Propagate(tm)
+	return vecNorm(Vector3{X: rj.X - ri.X, Y: rj.Y - ri.Y, Z: rj.Z - ri.Z})
+}
@@ -3,6 +3,7 @@ package satellite
 import (
 	"log"
 	"math"
+	"time"
 )
 
 // this procedure converts the day of the year, epochDays, to the equivalent month day, hour, minute and second.
@@ -37,6 +38,19 @@ func days2mdhms(year int64, epochDays float64) (mon, day, hr, min, sec float64)
 	return
 }
 
+// Converts a full year and fractional day of year (as carried in a TLE epoch) into a UTC time, rounded to the microsecond
+func epochTime(year int64, epochDays float64) time.Time {
+	start := time.Date(int(year), time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration(math.Round((epochDays-1)*86400e6)) * time.Microsecond)
+}
+
+// Converts a time into the full year and fractional day of year of a TLE epoch, the inverse of epochTime
+func timeToEpoch(t time.Time) (year int64, epochDays float64) {
+	t = t.UTC()
+	start := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	return int64(t.Year()), 1 + t.Sub(start).Hours()/24
+}
+
 // Calc julian date given year, month, day, hour, minute and second
 // the julian date is defined by each elapsed day since noon, jan 1, 4713 bc.
 func JDay(year, mon, day, hr, min, sec int) float64 {
@@ -56,44 +70,37 @@ func gstime(jdut1 float64) (temp float64) {
 	return
 }
 
+// Calc julian date given year, month, day, hour, minute and fractional second, split into the julian date at 0h
+// and the fraction of the day so that sub-second precision is not lost in the large whole part
+func JDayFrac(year, mon, day, hr, min int, sec float64) (jd, jdFrac float64) {
+	jd = JDay(year, mon, day, 0, 0, 0)
+	jdFrac = (sec + float64(min)*60.0 + float64(hr)*3600.0) / 86400.0
+
+	// keep the fraction within a single day
+	if jdFrac < 0.0 || jdFrac >= 1.0 {
+		whole := math.Floor(jdFrac)
+		jd += whole
+		jdFrac -= whole
+	}
+	return
+}
+
+// Calc the split julian date (see JDayFrac) of a time, the time is converted to UTC first
+func JDayTime(t time.Time) (jd, jdFrac float64) {
+	t = t.UTC()
+	sec := float64(t.Second()) + float64(t.Nanosecond())/1e9
+	return JDayFrac(t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), sec)
+}
+
 // Calc GST given year, month, day, hour, minute and second
 func GSTimeFromDate(year, mon, day, hr, min, sec int) float64 {
 	jDay := JDay(year, mon, day, hr, min, sec)
 	return gstime(jDay)
 }
 
-// Convert Earth Centered Inertial coordinated into equivalent latitude, longitude, altitude and velocity.
-// Reference: http://celestrak.com/columns/v02n03/
+// Convert Earth Centered Inertial coordinated into equivalent geodetic latitude, longitude and altitude on the WGS84 ellipsoid.
 func ECIToLLA(eciCoords Vector3, gmst float64) (ret LatLongAlt) {
-	a := 6378.137     // Semi-major Axis
-	b := 6356.7523142 // Semi-minor Axis
-	f := (a - b) / a  // Flattening
-	e2 := ((2 * f) - math.Pow(f, 2))
-
-	sqx2y2 := math.Sqrt(math.Pow(eciCoords.X, 2) + math.Pow(eciCoords.Y, 2))
-
-	// Spherical Earth Calculations
-	longitude := math.Atan2(eciCoords.Y, eciCoords.X) - gmst
-	latitude := math.Atan2(eciCoords.Z, sqx2y2)
-
-	// Oblate Earth Fix
-	C := 0.0
-	for i := 0; i < 20; i++ {
-		C = 1 / math.Sqrt(1-e2*(math.Sin(latitude)*math.Sin(latitude)))
-		latitude = math.Atan2(eciCoords.Z+(a*C*e2*math.Sin(latitude)), sqx2y2)
-	}
-
-	// Calc Alt
-	altitude := (sqx2y2 / math.Cos(latitude)) - (a * C)
-
-	// Orbital Speed ≈ sqrt(μ / r) where μ = std. gravitaional parameter
-	//velocity = math.Sqrt(398600.4418 / (altitude + 6378.137))
-
-	ret.Latitude = latitude
-	ret.Longitude = longitude
-	ret.Altitude = altitude
-
-	return
+	return ECEFToGeodetic(ECIToECEF(eciCoords, gmst))
 }
 
 // Convert LatLong in radians to LatLong in degrees
@@ -113,27 +120,16 @@ func LatLongDeg(rad LatLong) (deg LatLong) {
 }
 
 // Calculate GMST from Julian date.
-// Reference: The 1992 Astronomical Almanac, page B6.
+//
+// Deprecated: ThetaG_JD is kept for compatibility and returns GMST(jday).
 func ThetaG_JD(jday float64) (ret float64) {
-	_, UT := math.Modf(jday + 0.5)
-	jday = jday - UT
-	TU := (jday - 2451545.0) / 36525.0
-	GMST := 24110.54841 + TU*(8640184.812866+TU*(0.093104-TU*6.2e-6))
-	GMST = math.Mod(GMST+86400.0*1.00273790934*UT, 86400.0)
-	ret = 2 * math.Pi * GMST / 86400.0
-	return
+	return GMST(jday)
 }
 
-// Convert latitude, longitude and altitude(km) into equivalent Earth Centered Intertial coordinates(km)
-// Reference: The 1992 Astronomical Almanac, page K11.
+// Convert geodetic latitude, longitude and altitude(km) on the WGS84 ellipsoid into equivalent Earth Centered Intertial coordinates(km)
 func LLAToECI(obsCoords LatLong, alt, jday float64) (eciObs Vector3) {
-	re := 6378.137
-	theta := math.Mod(ThetaG_JD(jday)+obsCoords.Longitude, TWOPI)
-	r := (re + alt) * math.Cos(obsCoords.Latitude)
-	eciObs.X = r * math.Cos(theta)
-	eciObs.Y = r * math.Sin(theta)
-	eciObs.Z = (re + alt) * math.Sin(obsCoords.Latitude)
-	return
+	ecef := GeodeticToECEF(LatLongAlt{Latitude: obsCoords.Latitude, Longitude: obsCoords.Longitude, Altitude: alt})
+	return ECEFToECI(ecef, GMST(jday))
 }
 
 // Convert Earth Centered Intertial coordinates into Earth Cenetered Earth Final coordinates
@@ -145,25 +141,34 @@ func ECIToECEF(eciCoords Vector3, gmst float64) (ecfCoords Vector3) {
 	return
 }
 
+// Convert Earth Cenetered Earth Fixed coordinates into Earth Centered Intertial coordinates, the inverse of ECIToECEF
+func ECEFToECI(ecfCoords Vector3, gmst float64) (eciCoords Vector3) {
+	eciCoords.X = ecfCoords.X*math.Cos(gmst) - ecfCoords.Y*math.Sin(gmst)
+	eciCoords.Y = ecfCoords.X*math.Sin(gmst) + ecfCoords.Y*math.Cos(gmst)
+	eciCoords.Z = ecfCoords.Z
+	return
+}
+
 // Calculate look angles for given satellite position and observer position
-// obsAlt in km
+// obsCoords are geodetic (WGS84) in radians, obsAlt in km
 // Reference: http://celestrak.com/columns/v02n02/
 func ECIToLookAngles(eciSat Vector3, obsCoords LatLong, obsAlt, jday float64) (lookAngles LookAngles) {
-	theta := math.Mod(ThetaG_JD(jday)+obsCoords.Longitude, 2*math.Pi)
-	obsPos := LLAToECI(obsCoords, obsAlt, jday)
+	satPos := ECIToECEF(eciSat, GMST(jday))
+	obsPos := GeodeticToECEF(LatLongAlt{Latitude: obsCoords.Latitude, Longitude: obsCoords.Longitude, Altitude: obsAlt})
 
-	rx := eciSat.X - obsPos.X
-	ry := eciSat.Y - obsPos.Y
-	rz := eciSat.Z - obsPos.Z
+	rx := satPos.X - obsPos.X
+	ry := satPos.Y - obsPos.Y
+	rz := satPos.Z - obsPos.Z
 
-	top_s := math.Sin(obsCoords.Latitude)*math.Cos(theta)*rx + math.Sin(obsCoords.Latitude)*math.Sin(theta)*ry - math.Cos(obsCoords.Latitude)*rz
-	top_e := -math.Sin(theta)*rx + math.Cos(theta)*ry
-	top_z := math.Cos(obsCoords.Latitude)*math.Cos(theta)*rx + math.Cos(obsCoords.Latitude)*math.Sin(theta)*ry + math.Sin(obsCoords.Latitude)*rz
+	sinLat, cosLat := math.Sincos(obsCoords.Latitude)
+	sinLon, cosLon := math.Sincos(obsCoords.Longitude)
 
-	lookAngles.Az = math.Atan(-top_e / top_s)
-	if top_s > 0 {
-		lookAngles.Az = lookAngles.Az + math.Pi
-	}
+	// topocentric south, east, zenith components
+	top_s := sinLat*cosLon*rx + sinLat*sinLon*ry - cosLat*rz
+	top_e := -sinLon*rx + cosLon*ry
+	top_z := cosLat*cosLon*rx + cosLat*sinLon*ry + sinLat*rz
+
+	lookAngles.Az = math.Atan2(top_e, -top_s)
 	if lookAngles.Az < 0 {
 		lookAngles.Az = lookAngles.Az + 2*math.Pi
 	}
@@ -0,0 +1,84 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// Speed of light in vacuum (km/s)
+const speedOfLight float64 = 299792.458
+
+// Calculates the range (km) and range rate (km/s) between two objects from their positions (km) and velocities
+// (km/s) in the same inertial frame. The range rate is positive when the objects move apart.
+func RangeRate(r1, v1, r2, v2 Vector3) (rng, rate float64) {
+	dx, dy, dz := r2.X-r1.X, r2.Y-r1.Y, r2.Z-r1.Z
+	rng = math.Sqrt(dx*dx + dy*dy + dz*dz)
+	if rng == 0 {
+		return 0, 0
+	}
+	rate = (dx*(v2.X-v1.X) + dy*(v2.Y-v1.Y) + dz*(v2.Z-v1.Z)) / rng
+	return
+}
+
+// Calculates the first order Doppler shift (Hz) of a carrier of freq Hz for a range rate in km/s.
+// The shift is positive while the range decreases.
+func DopplerShift(rangeRate, freq float64) float64 {
+	return -freq * rangeRate / speedOfLight
+}
+
+// Calculates the TEME position and velocity of the ground station at t, the frame sgp4 positions are in.
+// The velocity is the Earth rotation, w x r.
+func (gs GroundStation) StateAt(t time.Time) (pos, vel TEMEVector) {
+	jd, jdFrac := JDayTime(t)
+	pos = TEMEVector(ECEFToECI(GeodeticToECEF(gs.Location), GMST(jd+jdFrac)))
+	vel = TEMEVector{X: -earthRotationRate * pos.Y, Y: earthRotationRate * pos.X, Z: 0}
+	return
+}
+
+// Calculates the range (km) and range rate (km/s) from the ground station to the satellite at t
+func (gs GroundStation) RangeRate(sat Satellite, t time.Time) (rng, rate float64, err error) {
+	satPos, satVel, err := PropagateTEME(sat, t)
+	if err != nil {
+		return 0, 0, err
+	}
+	gsPos, gsVel := gs.StateAt(t)
+	rng, rate = rangeRateTEME(gsPos, gsVel, satPos, satVel)
+	return
+}
+
+// Calculates the Doppler shift (Hz) of a carrier of freq Hz between the ground station and the satellite at t
+func (gs GroundStation) Doppler(sat Satellite, t time.Time, freq float64) (float64, error) {
+	_, rate, err := gs.RangeRate(sat, t)
+	if err != nil {
+		return 0, err
+	}
+	return DopplerShift(rate, freq), nil
+}
+
+// Calculates the range (km) and range rate (km/s) between two satellites at t
+func InterSatelliteRangeRate(a, b Satellite, t time.Time) (rng, rate float64, err error) {
+	posA, velA, err := PropagateTEME(a, t)
+	if err != nil {
+		return 0, 0, err
+	}
+	posB, velB, err := PropagateTEME(b, t)
+	if err != nil {
+		return 0, 0, err
+	}
+	rng, rate = rangeRateTEME(posA, velA, posB, velB)
+	return
+}
+
+// RangeRate for two states that are both in TEME
+func rangeRateTEME(posA, velA, posB, velB TEMEVector) (rng, rate float64) {
+	return RangeRate(Vector3(posA), Vector3(velA), Vector3(posB), Vector3(velB))
+}
+
+// Calculates the Doppler shift (Hz) of a carrier of freq Hz on the link between two satellites at t
+func InterSatelliteDoppler(a, b Satellite, t time.Time, freq float64) (float64, error) {
+	_, rate, err := InterSatelliteRangeRate(a, b, t)
+	if err != nil {
+		return 0, err
+	}
+	return DopplerShift(rate, freq), nil
+}
@@ -0,0 +1,55 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRangeRate(t *testing.T) {
+	sat := TLEToSat("1 00900U 64063C   22160.52204282  .00000408  00000+0  42495-3 0  9992",
+		"2 00900  90.1760  40.7701 0029467  47.9267  23.7177 13.73809888869573", GravityWGS84)
+	other := TLEToSat("1 00902U 64063E   22160.60603716  .00000042  00000+0  49695-4 0  9992",
+		"2 00902  90.1900  43.8467 0018732 164.5602 223.6614 13.52716145657714", GravityWGS84)
+	gs := GroundStation{Name: "Delft", Location: LatLongAlt{Latitude: 52.0 * DEG2RAD, Longitude: 4.37 * DEG2RAD, Altitude: 0.0}}
+	t0 := time.Date(2022, 6, 10, 3, 0, 0, 0, time.UTC)
+	dt := 500 * time.Millisecond
+
+	t.Run("Ground Station", func(t *testing.T) {
+		// the range rate must match the derivative of the topocentric range, which includes the Earth rotation
+		_, rate, err := gs.RangeRate(sat, t0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		before, _ := gs.LookAngles(sat, t0.Add(-dt))
+		after, _ := gs.LookAngles(sat, t0.Add(dt))
+		expected := (after.Rg - before.Rg) / (2 * dt.Seconds())
+		if math.Abs(rate-expected) > 1e-4 {
+			t.Errorf("Expected %f; but got %f", expected, rate)
+		}
+	})
+	t.Run("Inter-satellite", func(t *testing.T) {
+		_, rate, err := InterSatelliteRangeRate(sat, other, t0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		before, _, _ := InterSatelliteRangeRate(sat, other, t0.Add(-dt))
+		after, _, _ := InterSatelliteRangeRate(sat, other, t0.Add(dt))
+		expected := (after - before) / (2 * dt.Seconds())
+		if math.Abs(rate-expected) > 1e-4 {
+			t.Errorf("Expected %f; but got %f", expected, rate)
+		}
+	})
+	t.Run("Doppler", func(t *testing.T) {
+		// approaching at 7 km/s on 437 MHz
+		shift := DopplerShift(-7.0, 437e6)
+		if math.Abs(shift-10203.726) > 0.001 {
+			t.Errorf("Expected %f; but got %f", 10203.726, shift)
+		}
+		_, rate, _ := gs.RangeRate(sat, t0)
+		shift, err := gs.Doppler(sat, t0, 437e6)
+		if err != nil || shift != DopplerShift(rate, 437e6) {
+			t.Errorf("Expected %f; but got %f (%v)", DopplerShift(rate, 437e6), shift, err)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors reported by sgp4, one per error code set in Satellite.Error
+var (
+	ErrMeanEccentricity      = errors.New("mean eccentricity not within range 0.0 <= e < 1.0")
+	ErrMeanMotion            = errors.New("mean motion is less than zero")
+	ErrPerturbedEccentricity = errors.New("perturbed eccentricity not within range 0.0 <= e <= 1.0")
+	ErrSemiLatusRectum       = errors.New("semilatus rectum is less than zero")
+	ErrSubOrbital            = errors.New("epoch elements are sub-orbital")
+	ErrDecayed               = errors.New("satellite has decayed")
+	ErrUnknown               = errors.New("unknown sgp4 error")
+)
+
+// Errors of invalid SGP4Options, see TLEToSatWithOptions
+var (
+	ErrOpsMode = errors.New("invalid sgp4 operation mode")
+	ErrGravity = errors.New("invalid gravity model")
+)
+
+// Returned when sgp4 flags a propagation as invalid. Use errors.Is with the Err* values above to test for a specific cause.
+type PropagationError struct {
+	SatNum int64   // NORAD catalog number of the satellite
+	Code   int64   // sgp4 error code, as in Satellite.Error
+	Tsince float64 // minutes since epoch of the failed propagation
+	Err    error
+}
+
+func (e *PropagationError) Error() string {
+	return fmt.Sprintf("satellite %d at %.3f min from epoch: %v (sgp4 error %d)", e.SatNum, e.Tsince, e.Err, e.Code)
+}
+
+func (e *PropagationError) Unwrap() error {
+	return e.Err
+}
+
+// Maps an sgp4 error code to its typed error
+func sgp4CodeError(code int64) error {
+	switch code {
+	case 1:
+		return ErrMeanEccentricity
+	case 2:
+		return ErrMeanMotion
+	case 3:
+		return ErrPerturbedEccentricity
+	case 4:
+		return ErrSemiLatusRectum
+	case 5:
+		return ErrSubOrbital
+	case 6:
+		return ErrDecayed
+	default:
+		return ErrUnknown
+	}
+}
+
+// Returns a *PropagationError if the last sgp4 call on sat set an error code, nil otherwise
+func sgp4Error(sat *Satellite) error {
+	if sat.Error == 0 {
+		return nil
+	}
+	return &PropagationError{SatNum: sat.satnum, Code: sat.Error, Tsince: sat.t, Err: sgp4CodeError(sat.Error)}
+}
@@ -0,0 +1,287 @@
+package satellite
+
+import "math"
+
+// Position (km) or velocity (km/s) in the True Equator Mean Equinox frame, the frame of sgp4. PropagateTEME and
+// PropagateMinutesTEME return it typed; Propagate and friends return plain Vector3 values for compatibility.
+type TEMEVector Vector3
+
+// Position or velocity in the Pseudo Earth Fixed frame: Earth fixed, but without polar motion
+type PEFVector Vector3
+
+// Position or velocity in the International Terrestrial Reference Frame (Earth fixed, with polar motion)
+type ITRFVector Vector3
+
+// Position or velocity in the Geocentric Celestial Reference Frame, as realised by the IAU-76/FK5 J2000 frame
+type GCRFVector Vector3
+
+// Rotation rate of the Earth (rad/s)
+const earthRotationRate float64 = 7.292115146706979e-5
+
+// Rotates TEME coordinates into the PEF frame about the z axis by the Greenwich mean sidereal time.
+// jdut1 is the UT1 julian date, the velocity has the Earth rotation removed.
+// Reference: Vallado, Crawford, Hujsak and Kelso, "Revisiting Spacetrack Report #3", AIAA 2006-6753, teme2ecef
+func TEMEToPEF(r, v TEMEVector, jdut1 float64) (rpef, vpef PEFVector) {
+	st := rot3(GMST(jdut1))
+	rp := st.mul(Vector3(r))
+	vp := st.mul(Vector3(v))
+
+	// v_pef = st * v_teme - w x r_pef
+	vp.X += earthRotationRate * rp.Y
+	vp.Y -= earthRotationRate * rp.X
+	return PEFVector(rp), PEFVector(vp)
+}
+
+// Applies polar motion to PEF coordinates. xp and yp are the pole coordinates in radians (IERS values are
+// published in arcseconds, multiply by DEG2RAD/3600).
+func PEFToITRF(r, v PEFVector, xp, yp float64) (ritrf, vitrf ITRFVector) {
+	pm := polarMotion(xp, yp).transpose()
+	return ITRFVector(pm.mul(Vector3(r))), ITRFVector(pm.mul(Vector3(v)))
+}
+
+// Converts TEME coordinates into ITRF, see TEMEToPEF and PEFToITRF. Pass zero xp, yp to ignore polar motion.
+func TEMEToITRF(r, v TEMEVector, jdut1, xp, yp float64) (ritrf, vitrf ITRFVector) {
+	rpef, vpef := TEMEToPEF(r, v, jdut1)
+	return PEFToITRF(rpef, vpef, xp, yp)
+}
+
+// Converts TEME coordinates into GCRF by way of the true of date and mean of date frames, using the IAU-76
+// precession and IAU-80 nutation theories. jdtt is the TT julian date.
+// The corrections to the nutation published by the IERS (dPsi, dEps) are not applied, they amount to about a metre.
+// Reference: Vallado, Crawford, Hujsak and Kelso, "Revisiting Spacetrack Report #3", AIAA 2006-6753, teme2eci
+func TEMEToGCRF(r, v TEMEVector, jdtt float64) (rgcrf, vgcrf GCRFVector) {
+	return temeToGCRF(r, v, jdtt, 0, 0)
+}
+
+// Like TEMEToGCRF, with the nutation corrections ddpsi and ddeps in radians
+func temeToGCRF(r, v TEMEVector, jdtt, ddpsi, ddeps float64) (rgcrf, vgcrf GCRFVector) {
+	ttt := (jdtt - 2451545.0) / 36525.0
+	deltaPsi, meanEps, trueEps := nutation80(ttt, ddpsi, ddeps)
+
+	// TEME -> TOD by the equation of the equinoxes (without the kinematic terms, as sgp4 defines TEME)
+	eqe := deltaPsi * math.Cos(meanEps)
+	tm := rot3(-eqe)
+	// TOD -> MOD, the transpose of the nutation matrix N = R1(-trueEps) R3(-deltaPsi) R1(meanEps)
+	tm = rot1(-trueEps).mulm(rot3(-deltaPsi)).mulm(rot1(meanEps)).transpose().mulm(tm)
+	// MOD -> GCRF, the transpose of the precession matrix P = R3(-z) R2(theta) R3(-zeta)
+	tm = precession76(ttt).transpose().mulm(tm)
+
+	return GCRFVector(tm.mul(Vector3(r))), GCRFVector(tm.mul(Vector3(v)))
+}
+
+// Returns the IAU-76 precession matrix from the J2000 frame to the mean of date frame
+func precession76(ttt float64) matrix3 {
+	ttt2 := ttt * ttt
+	ttt3 := ttt2 * ttt
+	asec := DEG2RAD / 3600.0
+	zeta := (2306.2181*ttt + 0.30188*ttt2 + 0.017998*ttt3) * asec
+	theta := (2004.3109*ttt - 0.42665*ttt2 - 0.041833*ttt3) * asec
+	z := (2306.2181*ttt + 1.09468*ttt2 + 0.018203*ttt3) * asec
+	return rot3(-z).mulm(rot2(theta)).mulm(rot3(-zeta))
+}
+
+// One term of the IAU-80 nutation series: multipliers of the fundamental arguments D, M, M', F and Omega, then
+// the longitude (sine) and obliquity (cosine) coefficients and their rates, in units of 0.0001 arcsec
+type nutationTerm struct {
+	d, m, mp, f, om float64
+	a, at, b, bt    float64
+}
+
+// The 106 terms of the IAU-80 nutation series
+// Reference: Seidelmann, "1980 IAU Theory of Nutation: The Final Report of the IAU Working Group on Nutation",
+// Celestial Mechanics 27 (1982), as tabulated in Vallado's nut80.dat
+var nutation80Terms = [...]nutationTerm{
+	{0, 0, 0, 0, 1, -171996, -174.2, 92025, 8.9},
+	{0, 0, 0, 0, 2, 2062, 0.2, -895, 0.5},
+	{0, 0, -2, 2, 1, 46, 0, -24, 0},
+	{0, 0, 2, -2, 0, 11, 0, 0, 0},
+	{0, 0, -2, 2, 2, -3, 0, 1, 0},
+	{-1, -1, 1, 0, 0, -3, 0, 0, 0},
+	{-2, -2, 0, 2, 1, -2, 0, 1, 0},
+	{0, 0, 2, -2, 1, 1, 0, 0, 0},
+	{-2, 0, 0, 2, 2, -13187, -1.6, 5736, -3.1},
+	{0, 1, 0, 0, 0, 1426, -3.4, 54, -0.1},
+	{-2, 1, 0, 2, 2, -517, 1.2, 224, -0.6},
+	{-2, -1, 0, 2, 2, 217, -0.5, -95, 0.3},
+	{-2, 0, 0, 2, 1, 129, 0.1, -70, 0},
+	{-2, 0, 2, 0, 0, 48, 0, 1, 0},
+	{-2, 0, 0, 2, 0, -22, 0, 0, 0},
+	{0, 2, 0, 0, 0, 17, -0.1, 0, 0},
+	{0, 1, 0, 0, 1, -15, 0, 9, 0},
+	{-2, 2, 0, 2, 2, -16, 0.1, 7, 0},
+	{0, -1, 0, 0, 1, -12, 0, 6, 0},
+	{2, 0, -2, 0, 1, -6, 0, 3, 0},
+	{-2, -1, 0, 2, 1, -5, 0, 3, 0},
+	{-2, 0, 2, 0, 1, 4, 0, -2, 0},
+	{-2, 1, 0, 2, 1, 4, 0, -2, 0},
+	{-1, 0, 1, 0, 0, -4, 0, 0, 0},
+	{-2, 1, 2, 0, 0, 1, 0, 0, 0},
+	{2, 0, 0, -2, 1, 1, 0, 0, 0},
+	{2, 1, 0, -2, 0, -1, 0, 0, 0},
+	{0, 1, 0, 0, 2, 1, 0, 0, 0},
+	{1, 0, -1, 0, 1, 1, 0, 0, 0},
+	{-2, 1, 0, 2, 0, -1, 0, 0, 0},
+	{0, 0, 0, 2, 2, -2274, -0.2, 977, -0.5},
+	{0, 0, 1, 0, 0, 712, 0.1, -7, 0},
+	{0, 0, 0, 2, 1, -386, -0.4, 200, 0},
+	{0, 0, 1, 2, 2, -301, 0, 129, -0.1},
+	{-2, 0, 1, 0, 0, -158, 0, -1, 0},
+	{0, 0, -1, 2, 2, 123, 0, -53, 0},
+	{2, 0, 0, 0, 0, 63, 0, -2, 0},
+	{0, 0, 1, 0, 1, 63, 0.1, -33, 0},
+	{0, 0, -1, 0, 1, -58, -0.1, 32, 0},
+	{2, 0, -1, 2, 2, -59, 0, 26, 0},
+	{0, 0, 1, 2, 1, -51, 0, 27, 0},
+	{2, 0, 0, 2, 2, -38, 0, 16, 0},
+	{0, 0, 2, 0, 0, 29, 0, -1, 0},
+	{-2, 0, 1, 2, 2, 29, 0, -12, 0},
+	{0, 0, 2, 2, 2, -31, 0, 13, 0},
+	{0, 0, 0, 2, 0, 26, 0, -1, 0},
+	{0, 0, -1, 2, 1, 21, 0, -10, 0},
+	{2, 0, -1, 0, 1, 16, 0, -8, 0},
+	{-2, 0, 1, 0, 1, -13, 0, 7, 0},
+	{2, 0, -1, 2, 1, -10, 0, 5, 0},
+	{-2, 1, 1, 0, 0, -7, 0, 0, 0},
+	{0, 1, 0, 2, 2, 7, 0, -3, 0},
+	{0, -1, 0, 2, 2, -7, 0, 3, 0},
+	{2, 0, 1, 2, 2, -8, 0, 3, 0},
+	{2, 0, 1, 0, 0, 6, 0, 0, 0},
+	{-2, 0, 2, 2, 2, 6, 0, -3, 0},
+	{2, 0, 0, 0, 1, -6, 0, 3, 0},
+	{2, 0, 0, 2, 1, -7, 0, 3, 0},
+	{-2, 0, 1, 2, 1, 6, 0, -3, 0},
+	{-2, 0, 0, 0, 1, -5, 0, 3, 0},
+	{0, -1, 1, 0, 0, 5, 0, 0, 0},
+	{0, 0, 2, 2, 1, -5, 0, 3, 0},
+	{-2, 1, 0, 0, 0, -4, 0, 0, 0},
+	{0, 0, 1, -2, 0, 4, 0, 0, 0},
+	{1, 0, 0, 0, 0, -4, 0, 0, 0},
+	{0, 1, 1, 0, 0, -3, 0, 0, 0},
+	{0, 0, 1, 2, 0, 3, 0, 0, 0},
+	{0, -1, 1, 2, 2, -3, 0, 1, 0},
+	{2, -1, -1, 2, 2, -3, 0, 1, 0},
+	{0, 0, -2, 0, 1, -2, 0, 1, 0},
+	{0, 0, 3, 2, 2, -3, 0, 1, 0},
+	{2, -1, 0, 2, 2, -3, 0, 1, 0},
+	{0, 1, 1, 2, 2, 2, 0, -1, 0},
+	{-2, 0, -1, 2, 1, -2, 0, 1, 0},
+	{0, 0, 2, 0, 1, 2, 0, -1, 0},
+	{0, 0, 1, 0, 2, -2, 0, 1, 0},
+	{0, 0, 3, 0, 0, 2, 0, 0, 0},
+	{1, 0, 0, 2, 2, 2, 0, -1, 0},
+	{0, 0, -1, 0, 2, 1, 0, -1, 0},
+	{-4, 0, 1, 0, 0, -1, 0, 0, 0},
+	{2, 0, -2, 2, 2, 1, 0, -1, 0},
+	{4, 0, -1, 2, 2, -2, 0, 1, 0},
+	{-4, 0, 2, 0, 0, -1, 0, 0, 0},
+	{-2, 1, 1, 2, 2, 1, 0, -1, 0},
+	{2, 0, 1, 2, 1, -1, 0, 1, 0},
+	{4, 0, -2, 2, 2, -1, 0, 1, 0},
+	{0, 0, -1, 4, 2, 1, 0, 0, 0},
+	{-2, -1, 1, 0, 0, 1, 0, 0, 0},
+	{-2, 0, 2, 2, 1, 1, 0, -1, 0},
+	{2, 0, 2, 2, 2, -1, 0, 0, 0},
+	{2, 0, 1, 0, 1, -1, 0, 0, 0},
+	{-2, 0, 0, 4, 2, 1, 0, 0, 0},
+	{-2, 0, 3, 2, 2, 1, 0, 0, 0},
+	{-2, 0, 1, 2, 0, -1, 0, 0, 0},
+	{0, 1, 0, 2, 1, 1, 0, 0, 0},
+	{2, -1, -1, 0, 1, 1, 0, 0, 0},
+	{0, 0, 0, -2, 1, -1, 0, 0, 0},
+	{-1, 0, 0, 2, 2, -1, 0, 0, 0},
+	{2, 1, 0, 0, 0, -1, 0, 0, 0},
+	{-2, 0, 1, -2, 0, -1, 0, 0, 0},
+	{0, -1, 0, 2, 1, -1, 0, 0, 0},
+	{-2, 1, 1, 0, 1, -1, 0, 0, 0},
+	{2, 0, 1, -2, 0, -1, 0, 0, 0},
+	{2, 0, 2, 0, 0, 1, 0, 0, 0},
+	{4, 0, 0, 2, 2, -1, 0, 0, 0},
+	{1, 1, 0, 0, 0, 1, 0, 0, 0},
+}
+
+// Computes the IAU-80 nutation in longitude and the mean and true obliquity of the ecliptic (radians)
+// for ttt julian centuries of TT since J2000, adding the corrections ddpsi and ddeps (radians)
+func nutation80(ttt, ddpsi, ddeps float64) (deltaPsi, meanEps, trueEps float64) {
+	ttt2 := ttt * ttt
+	ttt3 := ttt2 * ttt
+	asec := DEG2RAD / 3600.0
+
+	meanEps = (84381.448 - 46.8150*ttt - 0.00059*ttt2 + 0.001813*ttt3) * asec
+
+	// Delaunay arguments (degrees): elongation of the moon, anomaly of the sun, anomaly of the moon,
+	// argument of latitude of the moon, node of the moon
+	d := (((0.019*ttt-6.891)*ttt+1602961601.3280)*ttt)/3600.0 + 297.85036306
+	m := (((-0.012*ttt-0.577)*ttt+129596581.2240)*ttt)/3600.0 + 357.52772333
+	mp := (((0.064*ttt+31.310)*ttt+1717915922.6330)*ttt)/3600.0 + 134.96298139
+	f := (((0.011*ttt-13.257)*ttt+1739527263.1370)*ttt)/3600.0 + 93.27191028
+	om := (((0.008*ttt+7.455)*ttt-6962890.5390)*ttt)/3600.0 + 125.04452222
+
+	deltaEps := 0.0
+	for _, n := range nutation80Terms {
+		arg := math.Mod(n.d*d+n.m*m+n.mp*mp+n.f*f+n.om*om, 360.0) * DEG2RAD
+		deltaPsi += (n.a + n.at*ttt) * math.Sin(arg)
+		deltaEps += (n.b + n.bt*ttt) * math.Cos(arg)
+	}
+	deltaPsi = deltaPsi*1e-4*asec + ddpsi
+	deltaEps = deltaEps*1e-4*asec + ddeps
+	trueEps = meanEps + deltaEps
+	return
+}
+
+// Returns the polar motion matrix W that takes ITRF coordinates into PEF (IAU-76/FK5 convention)
+func polarMotion(xp, yp float64) matrix3 {
+	sinxp, cosxp := math.Sincos(xp)
+	sinyp, cosyp := math.Sincos(yp)
+	return matrix3{
+		{cosxp, 0, -sinxp},
+		{sinxp * sinyp, cosyp, cosxp * sinyp},
+		{sinxp * cosyp, -sinyp, cosxp * cosyp},
+	}
+}
+
+// 3x3 rotation matrix, row major
+type matrix3 [3][3]float64
+
+// Rotation of the coordinate frame about the x axis by a radians
+func rot1(a float64) matrix3 {
+	s, c := math.Sincos(a)
+	return matrix3{{1, 0, 0}, {0, c, s}, {0, -s, c}}
+}
+
+// Rotation of the coordinate frame about the y axis by a radians
+func rot2(a float64) matrix3 {
+	s, c := math.Sincos(a)
+	return matrix3{{c, 0, -s}, {0, 1, 0}, {s, 0, c}}
+}
+
+// Rotation of the coordinate frame about the z axis by a radians
+func rot3(a float64) matrix3 {
+	s, c := math.Sincos(a)
+	return matrix3{{c, s, 0}, {-s, c, 0}, {0, 0, 1}}
+}
+
+func (m matrix3) mul(v Vector3) Vector3 {
+	return Vector3{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z,
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z,
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z,
+	}
+}
+
+func (m matrix3) mulm(n matrix3) (p matrix3) {
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			p[i][j] = m[i][0]*n[0][j] + m[i][1]*n[1][j] + m[i][2]*n[2][j]
+		}
+	}
+	return
+}
+
+func (m matrix3) transpose() (t matrix3) {
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			t[i][j] = m[j][i]
+		}
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Vallado, Fundamentals of Astrodynamics and Applications, example 3-15
+func TestFrames(t *testing.T) {
+	r := TEMEVector{X: 5094.18016210, Y: 6127.64465950, Z: 6380.34453270}
+	v := TEMEVector{X: -4.746131487, Y: 0.785818041, Z: 5.531931288}
+	utc := time.Date(2004, 4, 6, 7, 51, 28, 386009000, time.UTC)
+	jd, jdFrac := JDayTime(utc.Add(-439961900 * time.Nanosecond))
+	jdut1 := jd + jdFrac
+	jd, jdFrac = JDayTime(utc.Add(64184 * time.Millisecond))
+	jdtt := jd + jdFrac
+	xp := -0.140682 * DEG2RAD / 3600
+	yp := 0.333309 * DEG2RAD / 3600
+
+	near := func(a, b Vector3, tol float64) bool {
+		return math.Abs(a.X-b.X) < tol && math.Abs(a.Y-b.Y) < tol && math.Abs(a.Z-b.Z) < tol
+	}
+
+	t.Run("TEME To PEF", func(t *testing.T) {
+		rpef, _ := TEMEToPEF(r, v, jdut1)
+		expected := Vector3{X: -1033.4750313, Y: 7901.3055856, Z: 6380.3445327}
+		if !near(Vector3(rpef), expected, 1e-3) {
+			t.Errorf("Expected %v; but got %v", expected, rpef)
+		}
+	})
+	t.Run("TEME To ITRF", func(t *testing.T) {
+		ritrf, vitrf := TEMEToITRF(r, v, jdut1, xp, yp)
+		expected := Vector3{X: -1033.4793830, Y: 7901.2952754, Z: 6380.3565958}
+		if !near(Vector3(ritrf), expected, 1e-3) {
+			t.Errorf("Expected %v; but got %v", expected, ritrf)
+		}
+		expectedV := Vector3{X: -3.225636520, Y: -2.872451450, Z: 5.531924446}
+		if !near(Vector3(vitrf), expectedV, 1e-6) {
+			t.Errorf("Expected %v; but got %v", expectedV, vitrf)
+		}
+	})
+	t.Run("TEME To GCRF", func(t *testing.T) {
+		// the reference includes the EOP nutation corrections and the kinematic terms of the equation of the
+		// equinoxes, which TEME leaves out (~5 cm here)
+		ddpsi := -0.052195 * DEG2RAD / 3600
+		ddeps := -0.003875 * DEG2RAD / 3600
+		ttt := (jdtt - 2451545.0) / 36525.0
+		om := (125.04452222 - 6962890.5390*ttt/3600.0) * DEG2RAD
+		kinematic := rot3(-(0.00264*math.Sin(om) + 0.000063*math.Sin(2*om)) * DEG2RAD / 3600)
+		rgcrf, _ := temeToGCRF(TEMEVector(kinematic.mul(Vector3(r))), v, jdtt, ddpsi, ddeps)
+		expected := Vector3{X: 5102.508958, Y: 6123.011401, Z: 6378.136928}
+		if !near(Vector3(rgcrf), expected, 1e-6) {
+			t.Errorf("Expected %v; but got %v", expected, rgcrf)
+		}
+	})
+}
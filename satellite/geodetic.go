@@ -0,0 +1,58 @@
+package satellite
+
+import "math"
+
+// WGS84 reference ellipsoid
+const (
+	wgs84A  float64 = 6378.137                // Semi-major Axis (km)
+	wgs84F  float64 = 1.0 / 298.257223563     // Flattening
+	wgs84B  float64 = wgs84A * (1.0 - wgs84F) // Semi-minor Axis (km)
+	wgs84E2 float64 = wgs84F * (2.0 - wgs84F) // First eccentricity squared
+)
+
+// Convert Earth Centered Earth Fixed coordinates (km) into geodetic latitude, longitude (radians) and altitude (km)
+// above the WGS84 ellipsoid. Latitude is iterated until it changes by less than 1e-12 rad.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, algorithm 12
+func ECEFToGeodetic(ecef Vector3) (lla LatLongAlt) {
+	p := math.Sqrt(ecef.X*ecef.X + ecef.Y*ecef.Y)
+	lla.Longitude = math.Atan2(ecef.Y, ecef.X)
+
+	// On the polar axis latitude is +-90 and the iteration below is singular
+	if p < 1e-9 {
+		lla.Latitude = math.Copysign(math.Pi/2, ecef.Z)
+		lla.Altitude = math.Abs(ecef.Z) - wgs84B
+		return
+	}
+
+	lat := math.Atan2(ecef.Z, p*(1.0-wgs84E2))
+	for i := 0; i < 20; i++ {
+		sinLat := math.Sin(lat)
+		N := wgs84A / math.Sqrt(1.0-wgs84E2*sinLat*sinLat)
+		next := math.Atan2(ecef.Z+N*wgs84E2*sinLat, p)
+		done := math.Abs(next-lat) < 1e-12
+		lat = next
+		if done {
+			break
+		}
+	}
+
+	// altitude along the ellipsoid normal, well behaved at all latitudes
+	sinLat, cosLat := math.Sincos(lat)
+	N := wgs84A / math.Sqrt(1.0-wgs84E2*sinLat*sinLat)
+	lla.Latitude = lat
+	lla.Altitude = p*cosLat + (ecef.Z+wgs84E2*N*sinLat)*sinLat - N
+	return
+}
+
+// Convert geodetic latitude, longitude (radians) and altitude (km) above the WGS84 ellipsoid into Earth Centered
+// Earth Fixed coordinates (km)
+func GeodeticToECEF(lla LatLongAlt) (ecef Vector3) {
+	sinLat, cosLat := math.Sincos(lla.Latitude)
+	sinLon, cosLon := math.Sincos(lla.Longitude)
+	N := wgs84A / math.Sqrt(1.0-wgs84E2*sinLat*sinLat)
+
+	ecef.X = (N + lla.Altitude) * cosLat * cosLon
+	ecef.Y = (N + lla.Altitude) * cosLat * sinLon
+	ecef.Z = (N*(1.0-wgs84E2) + lla.Altitude) * sinLat
+	return
+}
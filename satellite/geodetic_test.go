@@ -0,0 +1,53 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeodetic(t *testing.T) {
+	t.Run("Vallado Example 3-3", func(t *testing.T) {
+		lla := ECEFToGeodetic(Vector3{X: 6524.834, Y: 6862.875, Z: 6448.296})
+		if math.Abs(lla.Latitude*RAD2DEG-34.352496) > 1e-5 {
+			t.Errorf("Expected %f; but got %f", 34.352496, lla.Latitude*RAD2DEG)
+		}
+		if math.Abs(lla.Longitude*RAD2DEG-46.4464) > 1e-4 {
+			t.Errorf("Expected %f; but got %f", 46.4464, lla.Longitude*RAD2DEG)
+		}
+		if math.Abs(lla.Altitude-5085.22) > 1e-2 {
+			t.Errorf("Expected %f; but got %f", 5085.22, lla.Altitude)
+		}
+	})
+	t.Run("Ellipsoid Surface", func(t *testing.T) {
+		equator := ECEFToGeodetic(Vector3{X: wgs84A})
+		pole := ECEFToGeodetic(Vector3{Z: -wgs84B})
+		if math.Abs(equator.Altitude) > 1e-9 || math.Abs(equator.Latitude) > 1e-12 {
+			t.Errorf("Expected zero latitude and altitude; but got %v", equator)
+		}
+		if math.Abs(pole.Altitude) > 1e-9 || pole.Latitude != -math.Pi/2 {
+			t.Errorf("Expected south pole at zero altitude; but got %v", pole)
+		}
+	})
+	t.Run("Round Trip", func(t *testing.T) {
+		for _, lat := range []float64{-89.999, -60, -1e-6, 0, 30, 45, 89.9} {
+			for _, lon := range []float64{-179, -90, 0, 120} {
+				for _, alt := range []float64{-1, 0, 0.5, 550, 20200, 35786} {
+					in := LatLongAlt{Latitude: lat * DEG2RAD, Longitude: lon * DEG2RAD, Altitude: alt}
+					out := ECEFToGeodetic(GeodeticToECEF(in))
+					if math.Abs(out.Latitude-in.Latitude) > 1e-11 || math.Abs(out.Longitude-in.Longitude) > 1e-11 || math.Abs(out.Altitude-in.Altitude) > 1e-7 {
+						t.Errorf("Expected %v; but got %v", in, out)
+					}
+				}
+			}
+		}
+	})
+	t.Run("Look Angles Overhead", func(t *testing.T) {
+		obs := LatLong{Latitude: 40 * DEG2RAD, Longitude: -105 * DEG2RAD}
+		jday := JDay(2022, 6, 1, 0, 0, 0)
+		sat := LLAToECI(obs, 500, jday)
+		look := ECIToLookAngles(sat, obs, 0, jday)
+		if math.Abs(look.El-math.Pi/2) > 1e-6 || math.Abs(look.Rg-500) > 1e-6 {
+			t.Errorf("Expected satellite at zenith 500 km away; but got %v", look)
+		}
+	})
+}
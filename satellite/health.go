@@ -0,0 +1,183 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Problems found by ValidateTLE
+var (
+	ErrTLEFormat   = errors.New("malformed TLE")
+	ErrTLEChecksum = errors.New("TLE checksum mismatch")
+)
+
+// Epoch age beyond which CheckHealth reports a record as stale when HealthOptions.MaxEpochAge is zero
+const DefaultMaxEpochAge = 14 * 24 * time.Hour
+
+// Checks the structure of an element set before it is handed to TLEToSat, which cannot report errors: line
+// numbers and lengths, matching catalog numbers, checksums and the numeric fields.
+// The returned error wraps ErrTLEFormat or ErrTLEChecksum.
+func ValidateTLE(line1, line2 string) error {
+	for i, line := range []string{line1, line2} {
+		if len(line) < 69 {
+			return fmt.Errorf("%w: line %d has %d columns instead of 69", ErrTLEFormat, i+1, len(line))
+		}
+		if line[0] != byte('1'+i) || line[1] != ' ' {
+			return fmt.Errorf("%w: line %d does not start with %q", ErrTLEFormat, i+1, string(rune('1'+i))+" ")
+		}
+		if sum := int(line[68] - '0'); sum != tleChecksum(line) {
+			return fmt.Errorf("%w: line %d has %q, expected %d", ErrTLEChecksum, i+1, line[68:69], tleChecksum(line))
+		}
+	}
+	if strings.TrimSpace(line1[2:7]) != strings.TrimSpace(line2[2:7]) {
+		return fmt.Errorf("%w: catalog numbers %q and %q differ", ErrTLEFormat, line1[2:7], line2[2:7])
+	}
+
+	// the fields as ParseTLE reads them
+	fields := []string{
+		strings.TrimSpace(line1[2:7]),
+		line1[18:20],
+		line1[20:32],
+		strings.Replace(line1[33:43], " ", "", 2),
+		strings.Replace(line1[44:45]+"."+line1[45:50]+"e"+line1[50:52], " ", "", 2),
+		strings.Replace(line1[53:54]+"."+line1[54:59]+"e"+line1[59:61], " ", "", 2),
+		strings.Replace(line2[8:16], " ", "", 2),
+		strings.Replace(line2[17:25], " ", "", 2),
+		"." + line2[26:33],
+		strings.Replace(line2[34:42], " ", "", 2),
+		strings.Replace(line2[43:51], " ", "", 2),
+		strings.Replace(line2[52:63], " ", "", 2),
+	}
+	for _, f := range fields {
+		if _, err := strconv.ParseFloat(f, 64); err != nil {
+			return fmt.Errorf("%w: field %q is not a number", ErrTLEFormat, f)
+		}
+	}
+	return nil
+}
+
+// Options for CheckHealth
+type HealthOptions struct {
+	MaxEpochAge time.Duration // records with an epoch further than this from the target are stale, defaults to DefaultMaxEpochAge
+}
+
+// Health of one catalog record
+type RecordHealth struct {
+	Index     int // index of the satellite in the catalog
+	Name      string
+	NoradID   int64         // zero for satellites without a TLE
+	Epoch     time.Time     // zero if the TLE is invalid
+	EpochAge  time.Duration // target - epoch, negative for epochs after the target
+	Stale     bool          // the epoch age exceeds HealthOptions.MaxEpochAge
+	Duplicate bool          // another record with the same NORAD id has a newer epoch
+	Err       error         // ValidateTLE or propagation error, nil if the record propagates to the target
+}
+
+// Reports whether the record can be used at the target time
+func (r RecordHealth) Healthy() bool {
+	return !r.Stale && !r.Duplicate && r.Err == nil
+}
+
+// Health of a catalog relative to a target time, see CheckHealth
+type HealthReport struct {
+	Target      time.Time
+	MaxEpochAge time.Duration
+	Records     []RecordHealth // one per catalog satellite, in catalog order
+
+	Stale               int
+	Duplicates          int
+	Invalid             int // TLEs rejected by ValidateTLE
+	PropagationFailures int
+}
+
+// Checks every record of the catalog: TLE structure and checksums, epoch age relative to target, duplicate
+// NORAD ids (the newest epoch wins) and propagation to target. The catalog is not modified.
+func CheckHealth(cat Catalog, target time.Time, opts HealthOptions) *HealthReport {
+	if opts.MaxEpochAge == 0 {
+		opts.MaxEpochAge = DefaultMaxEpochAge
+	}
+	report := &HealthReport{Target: target, MaxEpochAge: opts.MaxEpochAge, Records: make([]RecordHealth, len(cat))}
+	newest := make(map[int64]int) // NORAD id -> index of the record with the newest epoch
+
+	for i := range cat {
+		rec := RecordHealth{Index: i, Name: cat[i].Name}
+		if cat[i].prop == nil || cat[i].Ole1 != "" {
+			if err := ValidateTLE(cat[i].Ole1, cat[i].Ole2); err != nil {
+				rec.Err = err
+				report.Invalid++
+				report.Records[i] = rec
+				continue
+			}
+		}
+
+		prop := cat[i].propagator()
+		rec.Epoch = prop.Epoch()
+		rec.EpochAge = target.Sub(rec.Epoch)
+		rec.Stale = rec.EpochAge > opts.MaxEpochAge || rec.EpochAge < -opts.MaxEpochAge
+		if rec.Stale {
+			report.Stale++
+		}
+		if sgp4, ok := prop.(*SGP4Propagator); ok {
+			rec.NoradID = sgp4.Sat.NoradID()
+			if j, seen := newest[rec.NoradID]; !seen || report.Records[j].Epoch.Before(rec.Epoch) {
+				newest[rec.NoradID] = i
+			}
+		}
+		if _, _, err := prop.Propagate(target); err != nil {
+			rec.Err = err
+			report.PropagationFailures++
+		}
+		report.Records[i] = rec
+	}
+
+	for i := range report.Records {
+		rec := &report.Records[i]
+		if rec.NoradID != 0 && newest[rec.NoradID] != i {
+			rec.Duplicate = true
+			report.Duplicates++
+		}
+	}
+	return report
+}
+
+// Returns the healthy satellites of the catalog the report was made for
+func (r *HealthReport) Filter(cat Catalog) Catalog {
+	var out Catalog
+	for _, rec := range r.Records {
+		if rec.Healthy() {
+			out = append(out, cat[rec.Index])
+		}
+	}
+	return out
+}
+
+// Writes one line per unhealthy record followed by a summary
+func (r *HealthReport) Write(w io.Writer) error {
+	healthy := 0
+	for _, rec := range r.Records {
+		if rec.Healthy() {
+			healthy++
+			continue
+		}
+		var problems []string
+		if rec.Err != nil {
+			problems = append(problems, rec.Err.Error())
+		}
+		if rec.Stale {
+			problems = append(problems, fmt.Sprintf("stale, epoch %s is %.1f days from target", rec.Epoch.Format(time.RFC3339), rec.EpochAge.Hours()/24))
+		}
+		if rec.Duplicate {
+			problems = append(problems, "superseded by a newer element set")
+		}
+		if _, err := fmt.Fprintf(w, "%5d %-24s %6d  %s\n", rec.Index, rec.Name, rec.NoradID, strings.Join(problems, "; ")); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d of %d records healthy at %s: %d stale (> %v), %d duplicates, %d invalid, %d propagation failures\n",
+		healthy, len(r.Records), r.Target.Format(time.RFC3339), r.Stale, r.MaxEpochAge, r.Duplicates, r.Invalid, r.PropagationFailures)
+	return err
+}
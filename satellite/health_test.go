@@ -0,0 +1,86 @@
+package satellite
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateTLE(t *testing.T) {
+	l1 := "1 00900U 64063C   22160.52204282  .00000408  00000+0  42495-3 0  9992"
+	l2 := "2 00900  90.1760  40.7701 0029467  47.9267  23.7177 13.73809888869573"
+	withChecksum := func(line string) string { return line + string(rune('0'+tleChecksum(line))) }
+	cases := []struct {
+		name     string
+		l1, l2   string
+		expected error
+	}{
+		{"Valid", l1, l2, nil},
+		{"Checksum", l1[:68] + "3", l2, ErrTLEChecksum},
+		{"Short", l1[:60], l2, ErrTLEFormat},
+		{"Swapped", l2, l1, ErrTLEFormat},
+		{"Catalog number", l1, withChecksum("2 00901" + l2[7:68]), ErrTLEFormat},
+		{"Field", l1, withChecksum(l2[:8] + " 90.1x60" + l2[16:68]), ErrTLEFormat},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := ValidateTLE(c.l1, c.l2); !errors.Is(err, c.expected) || (c.expected == nil && err != nil) {
+				t.Errorf("Expected %v; but got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	good := SimpleSatellite{Name: "CALSPHERE 1",
+		Ole1: "1 00900U 64063C   22160.52204282  .00000408  00000+0  42495-3 0  9992",
+		Ole2: "2 00900  90.1760  40.7701 0029467  47.9267  23.7177 13.73809888869573"}
+	// an older element set of the same object
+	el := SatToElements(TLEToSat(good.Ole1, good.Ole2, GravityWGS84))
+	el.EpochDays -= 30
+	o1, o2, err := ElementsToTLE(el)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := SimpleSatellite{Name: "CALSPHERE 1 OLD", Ole1: o1, Ole2: o2}
+	corrupt := SimpleSatellite{Name: "CORRUPT", Ole1: good.Ole1[:68] + "0", Ole2: good.Ole2}
+	decayed := SimpleSatellite{Name: "DECAYED",
+		Ole1: "1 99999U          22160.50000000  .00000000  00000+0  50000-1 0    09",
+		Ole2: "2 99999  51.6000   0.0000 0005000   0.0000   0.0000 16.00000000    01"}
+
+	cat := Catalog{old, good, corrupt, decayed}
+	target := time.Date(2022, 6, 12, 0, 0, 0, 0, time.UTC)
+	report := CheckHealth(cat, target, HealthOptions{MaxEpochAge: 7 * 24 * time.Hour})
+
+	if report.Stale != 1 || report.Duplicates != 1 || report.Invalid != 1 || report.PropagationFailures != 1 {
+		t.Errorf("Unexpected summary %+v", report)
+	}
+	if r := report.Records[0]; !r.Stale || !r.Duplicate || r.Err != nil {
+		t.Errorf("Expected a stale duplicate; but got %+v", r)
+	}
+	if r := report.Records[1]; !r.Healthy() || r.NoradID != 900 || r.EpochAge.Hours() < 59 || r.EpochAge.Hours() > 60 {
+		t.Errorf("Expected a healthy record about 59.5 h old; but got %+v", r)
+	}
+	if !errors.Is(report.Records[2].Err, ErrTLEChecksum) {
+		t.Errorf("Expected %v; but got %v", ErrTLEChecksum, report.Records[2].Err)
+	}
+	var propErr *PropagationError
+	if !errors.As(report.Records[3].Err, &propErr) {
+		t.Errorf("Expected a propagation error; but got %v", report.Records[3].Err)
+	}
+
+	healthy := report.Filter(cat)
+	if len(healthy) != 1 || healthy[0].Name != "CALSPHERE 1" {
+		t.Errorf("Expected only CALSPHERE 1; but got %v", healthy)
+	}
+
+	var out bytes.Buffer
+	if err := report.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 4 || !strings.HasPrefix(lines[3], "1 of 4 records healthy") {
+		t.Errorf("Unexpected report:\n%s", out.String())
+	}
+}
@@ -1,6 +1,7 @@
 package satellite
 
 import (
+	"fmt"
 	"log"
 	"math"
 	"strconv"
@@ -47,6 +48,8 @@ func ParseTLE(line1, line2 string, gravConst Gravity) (sat Satellite) {
 
 	// LINE 1 BEGIN
 	sat.satnum = parseInt(strings.TrimSpace(line1[2:7]))
+	sat.classification = line1[7:8]
+	sat.intldesg = strings.TrimSpace(line1[9:17])
 	sat.epochyr = parseInt(line1[18:20])
 	sat.epochdays = parseFloat(line1[20:32])
 
@@ -54,6 +57,7 @@ func ParseTLE(line1, line2 string, gravConst Gravity) (sat Satellite) {
 	sat.ndot = parseFloat(strings.Replace(line1[33:43], " ", "", 2))
 	sat.nddot = parseFloat(strings.Replace(line1[44:45]+"."+line1[45:50]+"e"+line1[50:52], " ", "", 2))
 	sat.bstar = parseFloat(strings.Replace(line1[53:54]+"."+line1[54:59]+"e"+line1[59:61], " ", "", 2))
+	sat.elnum = parseOptInt(tleField(line1, 64, 68))
 	// LINE 1 END
 
 	// LINE 2 BEGIN
@@ -63,18 +67,59 @@ func ParseTLE(line1, line2 string, gravConst Gravity) (sat Satellite) {
 	sat.argpo = parseFloat(strings.Replace(line2[34:42], " ", "", 2))
 	sat.mo = parseFloat(strings.Replace(line2[43:51], " ", "", 2))
 	sat.no = parseFloat(strings.Replace(line2[52:63], " ", "", 2))
+	sat.revnum = parseOptInt(tleField(line2, 63, 68))
 	// LINE 2 END
 	return
 }
 
-// Converts a two line element data set into a Satellite struct and runs sgp4init
+// Selects between the sgp4 operation modes
+type OpsMode string
+
+const (
+	OpsModeAFSPC    OpsMode = "a" // reproduces the operational AFSPC code (legacy sidereal time and node handling)
+	OpsModeImproved OpsMode = "i" // improved mode, the default
+)
+
+// Options for TLEToSatWithOptions, zero values select the defaults
+type SGP4Options struct {
+	OpsMode OpsMode // defaults to OpsModeImproved
+	Gravity Gravity // defaults to GravityWGS72, the model TLEs are generated with
+}
+
+// Converts a two line element data set into a Satellite struct and runs sgp4init in improved mode
 func TLEToSat(line1, line2 string, gravConst Gravity) Satellite {
+	return tleToSat(line1, line2, SGP4Options{OpsMode: OpsModeImproved, Gravity: gravConst})
+}
+
+// Converts a two line element data set into a Satellite struct and runs sgp4init with the given operation mode and gravity model.
+// Returns an error wrapping ErrOpsMode or ErrGravity for options it does not know, and a *PropagationError
+// along with the satellite if sgp4init flags the element set.
+func TLEToSatWithOptions(line1, line2 string, opts SGP4Options) (Satellite, error) {
+	if opts.Gravity == "" {
+		opts.Gravity = GravityWGS72
+	}
+	if opts.OpsMode == "" {
+		opts.OpsMode = OpsModeImproved
+	}
+	if opts.OpsMode != OpsModeAFSPC && opts.OpsMode != OpsModeImproved {
+		return Satellite{}, fmt.Errorf("%w %q", ErrOpsMode, opts.OpsMode)
+	}
+	if opts.Gravity != GravityWGS72Old && opts.Gravity != GravityWGS72 && opts.Gravity != GravityWGS84 {
+		return Satellite{}, fmt.Errorf("%w %q", ErrGravity, opts.Gravity)
+	}
+	sat := tleToSat(line1, line2, opts)
+	return sat, sgp4Error(&sat)
+}
+
+// Runs sgp4init with options already checked by the callers
+func tleToSat(line1, line2 string, opts SGP4Options) Satellite {
 	//sat := Satellite{Line1: line1, Line2: line2}
-	sat := ParseTLE(line1, line2, gravConst)
+	sat := ParseTLE(line1, line2, opts.Gravity)
 
-	opsmode := "i"
+	opsmode := string(opts.OpsMode)
 
 	sat.no = sat.no / XPDOTP
+	sat.noKozai = sat.no
 	sat.ndot = sat.ndot / (XPDOTP * 1440.0)
 	sat.nddot = sat.nddot / (XPDOTP * 1440.0 * 1440)
 
@@ -83,22 +128,36 @@ func TLEToSat(line1, line2 string, gravConst Gravity) Satellite {
 	sat.argpo = sat.argpo * DEG2RAD
 	sat.mo = sat.mo * DEG2RAD
 
-	var year int64 = 0
-	if sat.epochyr < 57 {
-		year = sat.epochyr + 2000
-	} else {
-		year = sat.epochyr + 1900
-	}
+	year := epochYear(sat.epochyr)
 
 	mon, day, hr, min, sec := days2mdhms(year, sat.epochdays)
 
-	sat.jdsatepoch = JDay(int(year), int(mon), int(day), int(hr), int(min), int(sec))
+	sat.jdsatepoch, sat.jdsatepochF = JDayFrac(int(year), int(mon), int(day), int(hr), int(min), sec)
 
-	sgp4init(&opsmode, sat.jdsatepoch-2433281.5, &sat)
+	sgp4init(&opsmode, (sat.jdsatepoch+sat.jdsatepochF)-2433281.5, &sat)
 
 	return sat
 }
 
+// Expands the two digit TLE epoch year into a full year (57-99 -> 1900s, 00-56 -> 2000s)
+func epochYear(yr int64) int64 {
+	if yr < 57 {
+		return yr + 2000
+	}
+	return yr + 1900
+}
+
+// Returns columns [lo, hi) of a TLE line, or whatever part of them exists for short lines
+func tleField(line string, lo, hi int) string {
+	if lo >= len(line) {
+		return ""
+	}
+	if hi > len(line) {
+		hi = len(line)
+	}
+	return line[lo:hi]
+}
+
 // Parses a string into a float64 value.
 func parseFloat(strIn string) (ret float64) {
 	ret, err := strconv.ParseFloat(strIn, 64)
@@ -116,3 +175,12 @@ func parseInt(strIn string) (ret int64) {
 	}
 	return ret
 }
+
+// Parses an optional integer field, blank fields are read as zero.
+func parseOptInt(strIn string) int64 {
+	strIn = strings.TrimSpace(strIn)
+	if strIn == "" {
+		return 0
+	}
+	return parseInt(strIn)
+}
@@ -0,0 +1,171 @@
+package satellite
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// TLEHistory stores several element sets per object, keyed by NORAD id and sorted by epoch, and selects the
+// set closest to a propagation time. It is persisted as a three line element file.
+type TLEHistory struct {
+	sets map[int64][]historyEntry
+}
+
+type historyEntry struct {
+	epoch time.Time
+	sat   SimpleSatellite
+}
+
+// Creates an empty history
+func NewTLEHistory() *TLEHistory {
+	return &TLEHistory{sets: make(map[int64][]historyEntry)}
+}
+
+// Reads a history from a TLE file, see ReadCatalog. A missing file yields an empty history, so a store can be
+// started from scratch and saved later.
+func LoadTLEHistory(path string) (*TLEHistory, error) {
+	h := NewTLEHistory()
+	cat, err := LoadCatalog(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.AddCatalog(cat); err != nil {
+		return h, err
+	}
+	return h, nil
+}
+
+// Adds an element set. A set with the same NORAD id and epoch as a stored one replaces it.
+// Returns the ValidateTLE error for malformed sets, which are not added.
+func (h *TLEHistory) Add(s SimpleSatellite) error {
+	if err := ValidateTLE(s.Ole1, s.Ole2); err != nil {
+		return fmt.Errorf("%s: %w", s.Name, err)
+	}
+	sat := ParseTLE(s.Ole1, s.Ole2, GravityWGS84)
+	entry := historyEntry{epoch: sat.Epoch(), sat: SimpleSatellite{Name: s.Name, Ole1: s.Ole1, Ole2: s.Ole2}}
+
+	sets := h.sets[sat.NoradID()]
+	i := sort.Search(len(sets), func(i int) bool { return !sets[i].epoch.Before(entry.epoch) })
+	if i < len(sets) && sets[i].epoch.Equal(entry.epoch) {
+		sets[i] = entry
+		return nil
+	}
+	sets = append(sets, historyEntry{})
+	copy(sets[i+1:], sets[i:])
+	sets[i] = entry
+	h.sets[sat.NoradID()] = sets
+	return nil
+}
+
+// Adds every element set of the catalog. Malformed sets are skipped; the returned error then reports how many
+// were skipped and the first failure.
+func (h *TLEHistory) AddCatalog(cat Catalog) (added int, err error) {
+	skipped := 0
+	for i := range cat {
+		if aerr := h.Add(cat[i]); aerr != nil {
+			if err == nil {
+				err = aerr
+			}
+			skipped++
+			continue
+		}
+		added++
+	}
+	if skipped > 0 {
+		err = fmt.Errorf("%d of %d element sets skipped, first %w", skipped, len(cat), err)
+	}
+	return added, err
+}
+
+// Returns the NORAD ids in the history, in ascending order
+func (h *TLEHistory) IDs() []int64 {
+	ids := make([]int64, 0, len(h.sets))
+	for id := range h.sets {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+	return ids
+}
+
+// Returns the element sets of one object, oldest first
+func (h *TLEHistory) ElementSets(id int64) Catalog {
+	sets := h.sets[id]
+	cat := make(Catalog, len(sets))
+	for i, e := range sets {
+		cat[i] = e.sat
+	}
+	return cat
+}
+
+// Returns the element set of the object whose epoch is closest to t (the older one on a tie), and false if
+// the object is not in the history
+func (h *TLEHistory) Nearest(id int64, t time.Time) (SimpleSatellite, bool) {
+	sets := h.sets[id]
+	if len(sets) == 0 {
+		return SimpleSatellite{}, false
+	}
+	i := sort.Search(len(sets), func(i int) bool { return sets[i].epoch.After(t) })
+	switch {
+	case i == 0:
+		return sets[0].sat, true
+	case i == len(sets):
+		return sets[i-1].sat, true
+	case sets[i].epoch.Sub(t) < t.Sub(sets[i-1].epoch):
+		return sets[i].sat, true
+	}
+	return sets[i-1].sat, true
+}
+
+// Returns a catalog with the element set nearest to t for every object, ordered by NORAD id
+func (h *TLEHistory) CatalogAt(t time.Time) Catalog {
+	ids := h.IDs()
+	cat := make(Catalog, 0, len(ids))
+	for _, id := range ids {
+		s, _ := h.Nearest(id, t)
+		cat = append(cat, s)
+	}
+	return cat
+}
+
+// Writes the history as a three line element file, ordered by NORAD id and epoch. The file is written to a
+// temporary file first and renamed, so an interrupted save leaves the previous file intact. An existing file
+// keeps its permissions, a new one is created with mode 0644.
+func (h *TLEHistory) Save(path string) error {
+	mode := os.FileMode(0644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+	// CreateTemp uses mode 0600, which the rename would hand on to the history file
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	w := bufio.NewWriter(tmp)
+	for _, id := range h.IDs() {
+		for _, e := range h.sets[id] {
+			fmt.Fprintf(w, "%s\n%s\n%s\n", e.sat.Name, e.sat.Ole1, e.sat.Ole2)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,105 @@
+package satellite
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestTLEHistory(t *testing.T) {
+	cur := SimpleSatellite{Name: "CALSPHERE 1",
+		Ole1: "1 00900U 64063C   22160.52204282  .00000408  00000+0  42495-3 0  9992",
+		Ole2: "2 00900  90.1760  40.7701 0029467  47.9267  23.7177 13.73809888869573"}
+	epochSet := func(name string, days float64) SimpleSatellite {
+		el := SatToElements(TLEToSat(cur.Ole1, cur.Ole2, GravityWGS84))
+		el.EpochDays += days
+		l1, l2, err := ElementsToTLE(el)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return SimpleSatellite{Name: name, Ole1: l1, Ole2: l2}
+	}
+	older, newer := epochSet("OLDER", -10), epochSet("NEWER", 10)
+	iss := SimpleSatellite{Name: "ISS (ZARYA)",
+		Ole1: "1 25544U 98067A   22160.65257817  .00006058  00000+0  11431-3 0  9993",
+		Ole2: "2 25544  51.6455  13.9080 0004408 213.1944 218.8771 15.49930568344015"}
+	corrupt := SimpleSatellite{Name: "CORRUPT", Ole1: cur.Ole1[:68] + "0", Ole2: cur.Ole2}
+
+	h := NewTLEHistory()
+	added, err := h.AddCatalog(Catalog{newer, cur, corrupt, iss, older})
+	if added != 4 || !errors.Is(err, ErrTLEChecksum) {
+		t.Errorf("Expected 4 sets added and a checksum error; but got %d, %v", added, err)
+	}
+	if sets := h.ElementSets(900); len(sets) != 3 || sets[0].Name != "OLDER" || sets[2].Name != "NEWER" {
+		t.Errorf("Expected the sets of 900 sorted by epoch; but got %v", sets)
+	}
+
+	epoch := TLEToSat(cur.Ole1, cur.Ole2, GravityWGS84).Epoch()
+	cases := []struct {
+		name     string
+		t        time.Time
+		expected string
+	}{
+		{"Before all", epoch.AddDate(0, 0, -30), "OLDER"},
+		{"Closer to older", epoch.AddDate(0, 0, -6), "OLDER"},
+		{"Closer to current", epoch.AddDate(0, 0, -4), "CALSPHERE 1"},
+		{"At epoch", epoch, "CALSPHERE 1"},
+		{"Tie", epoch.AddDate(0, 0, 5), "CALSPHERE 1"},
+		{"After all", epoch.AddDate(1, 0, 0), "NEWER"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if s, ok := h.Nearest(900, c.t); !ok || s.Name != c.expected {
+				t.Errorf("Expected %s; but got %s", c.expected, s.Name)
+			}
+		})
+	}
+	if _, ok := h.Nearest(12345, epoch); ok {
+		t.Errorf("Expected no element set for an unknown object")
+	}
+
+	t.Run("Save and load", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "history.txt")
+		empty, err := LoadTLEHistory(path)
+		if err != nil || len(empty.IDs()) != 0 {
+			t.Fatalf("Expected an empty history for a missing file; but got %v, %v", empty.IDs(), err)
+		}
+		if err := h.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadTLEHistory(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cat := loaded.CatalogAt(epoch.AddDate(0, 0, 8))
+		if len(cat) != 2 || cat[0].Name != "NEWER" || cat[1].Name != "ISS (ZARYA)" || cat[0].Ole1 != newer.Ole1 {
+			t.Errorf("Unexpected catalog %v", cat)
+		}
+	})
+
+	t.Run("Save keeps the file mode", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("no unix permissions")
+		}
+		path := filepath.Join(t.TempDir(), "history.txt")
+		for _, expected := range []os.FileMode{0644, 0640} {
+			if err := h.Save(path); err != nil {
+				t.Fatal(err)
+			}
+			fi, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fi.Mode().Perm() != expected {
+				t.Errorf("Expected %v; but got %v", expected, fi.Mode().Perm())
+			}
+			// the second save must keep a mode set by the user
+			if err := os.Chmod(path, 0640); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,263 @@
+package satellite
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// A static k-d tree over 3D positions, e.g. the ECI positions of a propagated catalog, answering range and
+// k-nearest neighbour queries in about O(log n) per result instead of scanning all positions.
+// The tree is balanced, split at the median of the axis with the largest spread. Points are referred to by
+// their index in the slice the tree was built from.
+type KDTree struct {
+	points []Vector3
+	index  []int   // point indices in tree order, the node of a subrange [lo, hi) is its middle element
+	axis   []uint8 // split axis of the node at each position of index
+}
+
+// A point found by a KDTree query
+type Neighbor struct {
+	Index    int     // index of the point in the slice the tree was built from
+	Distance float64 // distance to the query position
+}
+
+// Builds a k-d tree over the points. The slice is kept by the tree and must not be modified while it is in use.
+func NewKDTree(points []Vector3) *KDTree {
+	t := &KDTree{points: points, index: make([]int, len(points)), axis: make([]uint8, len(points))}
+	for i := range t.index {
+		t.index[i] = i
+	}
+	t.build(0, len(points))
+	return t
+}
+
+// Returns the number of points in the tree
+func (t *KDTree) Len() int {
+	return len(t.points)
+}
+
+// Returns the point with the given index
+func (t *KDTree) Point(i int) Vector3 {
+	return t.points[i]
+}
+
+// Returns the points within radius of center (inclusive), ordered by index
+func (t *KDTree) Range(center Vector3, radius float64) []Neighbor {
+	var found []Neighbor
+	t.search(0, len(t.index), center, func() float64 { return radius }, func(i int, d float64) {
+		if d <= radius {
+			found = append(found, Neighbor{Index: i, Distance: d})
+		}
+	}, nil)
+	sort.Slice(found, func(a, b int) bool { return found[a].Index < found[b].Index })
+	return found
+}
+
+// Returns the k points nearest to center, closest first (ties by index). Fewer are returned if the tree holds
+// fewer than k points.
+func (t *KDTree) Nearest(center Vector3, k int) []Neighbor {
+	return t.NearestFunc(center, k, nil)
+}
+
+// Like Nearest, but only considers the points for which accept returns true. A nil accept considers all points.
+func (t *KDTree) NearestFunc(center Vector3, k int, accept func(i int) bool) []Neighbor {
+	if k <= 0 {
+		return nil
+	}
+	best := &neighborHeap{}
+	bound := func() float64 {
+		if best.Len() < k {
+			return math.Inf(1)
+		}
+		return (*best)[0].Distance
+	}
+	t.search(0, len(t.index), center, bound, func(i int, d float64) {
+		if accept != nil && !accept(i) {
+			return
+		}
+		n := Neighbor{Index: i, Distance: d}
+		if best.Len() < k {
+			heap.Push(best, n)
+		} else if neighborLess(n, (*best)[0]) {
+			(*best)[0] = n
+			heap.Fix(best, 0)
+		}
+	}, nil)
+	found := make([]Neighbor, best.Len())
+	for i := len(found) - 1; i >= 0; i-- {
+		found[i] = heap.Pop(best).(Neighbor)
+	}
+	return found
+}
+
+// Labels of the points of a KDTree, e.g. the components of a spanning forest under construction, for
+// NearestOther queries. Every node records whether all points of its subtree share one label, so a query can
+// skip such a subtree as a whole instead of visiting its points one by one.
+type KDLabels struct {
+	tree  *KDTree
+	label []int // label of every point, by index
+	node  []int // label shared by the subtree of the node at each position of index, -1 if mixed
+}
+
+// Labels the points of the tree, labels[i] >= 0 being the label of point i, in O(n). The slice is kept and must
+// not be modified while the labels are in use.
+func (t *KDTree) Label(labels []int) *KDLabels {
+	l := &KDLabels{tree: t, label: labels, node: make([]int, len(t.index))}
+	l.build(0, len(t.index))
+	return l
+}
+
+// Records the shared labels of the subtree [lo, hi) and returns its own, -1 if mixed and -2 if empty
+func (l *KDLabels) build(lo, hi int) int {
+	if lo >= hi {
+		return -2
+	}
+	mid := (lo + hi) / 2
+	label := l.label[l.tree.index[mid]]
+	for _, sub := range [2]int{l.build(lo, mid), l.build(mid+1, hi)} {
+		if sub != -2 && sub != label {
+			label = -1
+		}
+	}
+	l.node[mid] = label
+	return label
+}
+
+// Returns the point nearest to center (ties by index) whose label differs from label, within maxDist
+// (inclusive, math.Inf(1) for no limit). ok is false if there is no such point.
+func (l *KDLabels) NearestOther(center Vector3, label int, maxDist float64) (nearest Neighbor, ok bool) {
+	nearest = Neighbor{Index: -1, Distance: maxDist}
+	t := l.tree
+	t.search(0, len(t.index), center, func() float64 { return nearest.Distance }, func(i int, d float64) {
+		if l.label[i] == label || d > nearest.Distance {
+			return
+		}
+		if n := (Neighbor{Index: i, Distance: d}); nearest.Index < 0 || neighborLess(n, nearest) {
+			nearest = n
+		}
+	}, func(mid int) bool { return l.node[mid] == label })
+	return nearest, nearest.Index >= 0
+}
+
+// Arranges index[lo:hi] into a subtree: the median along the widest axis goes to the middle, smaller coordinates
+// before and larger after
+func (t *KDTree) build(lo, hi int) {
+	if hi-lo <= 1 {
+		return
+	}
+	var minP, maxP [3]float64
+	for k := range minP {
+		minP[k], maxP[k] = math.Inf(1), math.Inf(-1)
+	}
+	for _, i := range t.index[lo:hi] {
+		c := kdCoords(t.points[i])
+		for k := range c {
+			minP[k] = math.Min(minP[k], c[k])
+			maxP[k] = math.Max(maxP[k], c[k])
+		}
+	}
+	axis := 0
+	for k := 1; k < 3; k++ {
+		if maxP[k]-minP[k] > maxP[axis]-minP[axis] {
+			axis = k
+		}
+	}
+
+	mid := (lo + hi) / 2
+	t.selectMedian(lo, hi, mid, axis)
+	t.axis[mid] = uint8(axis)
+	t.build(lo, mid)
+	t.build(mid+1, hi)
+}
+
+// Partially sorts index[lo:hi] along the axis so that position mid holds the element it would hold if sorted
+// (quickselect with a median of three pivot)
+func (t *KDTree) selectMedian(lo, hi, mid, axis int) {
+	coord := func(p int) float64 { return kdCoords(t.points[t.index[p]])[axis] }
+	swap := func(a, b int) { t.index[a], t.index[b] = t.index[b], t.index[a] }
+	for hi-lo > 1 {
+		// median of three to the end as pivot
+		m := lo + (hi-lo)/2
+		if coord(m) < coord(lo) {
+			swap(m, lo)
+		}
+		if coord(hi-1) < coord(lo) {
+			swap(hi-1, lo)
+		}
+		if coord(m) < coord(hi-1) {
+			swap(m, hi-1)
+		}
+		pivot := coord(hi - 1)
+		store := lo
+		for p := lo; p < hi-1; p++ {
+			if coord(p) < pivot {
+				swap(p, store)
+				store++
+			}
+		}
+		swap(store, hi-1)
+		switch {
+		case mid < store:
+			hi = store
+		case mid > store:
+			lo = store + 1
+		default:
+			return
+		}
+	}
+}
+
+// Visits the points of the subtree [lo, hi) that may lie within bound() of center, nearer side first. Subtrees
+// for which skip (if not nil) returns true for their node position are left out.
+func (t *KDTree) search(lo, hi int, center Vector3, bound func() float64, visit func(i int, d float64), skip func(mid int) bool) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	if skip != nil && skip(mid) {
+		return
+	}
+	i := t.index[mid]
+	p := t.points[i]
+	visit(i, vecNorm(Vector3{X: p.X - center.X, Y: p.Y - center.Y, Z: p.Z - center.Z}))
+	if hi-lo == 1 {
+		return
+	}
+
+	axis := t.axis[mid]
+	diff := kdCoords(center)[axis] - kdCoords(p)[axis]
+	nearLo, nearHi, farLo, farHi := lo, mid, mid+1, hi
+	if diff > 0 {
+		nearLo, nearHi, farLo, farHi = mid+1, hi, lo, mid
+	}
+	t.search(nearLo, nearHi, center, bound, visit, skip)
+	if math.Abs(diff) <= bound() {
+		t.search(farLo, farHi, center, bound, visit, skip)
+	}
+}
+
+func kdCoords(p Vector3) [3]float64 {
+	return [3]float64{p.X, p.Y, p.Z}
+}
+
+func neighborLess(a, b Neighbor) bool {
+	if a.Distance != b.Distance {
+		return a.Distance < b.Distance
+	}
+	return a.Index < b.Index
+}
+
+// Max-heap of the best neighbours found so far, the worst on top
+type neighborHeap []Neighbor
+
+func (h neighborHeap) Len() int            { return len(h) }
+func (h neighborHeap) Less(a, b int) bool  { return neighborLess(h[b], h[a]) }
+func (h neighborHeap) Swap(a, b int)       { h[a], h[b] = h[b], h[a] }
+func (h *neighborHeap) Push(x interface{}) { *h = append(*h, x.(Neighbor)) }
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,112 @@
+package satellite
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestKDTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	points := make([]Vector3, 500)
+	for i := range points {
+		points[i] = Vector3{X: rng.Float64()*14000 - 7000, Y: rng.Float64()*14000 - 7000, Z: rng.Float64()*2000 - 1000}
+	}
+	// duplicates must not confuse the median split
+	points[10], points[11] = points[5], points[5]
+	tree := NewKDTree(points)
+
+	bruteForce := func(center Vector3) []Neighbor {
+		all := make([]Neighbor, len(points))
+		for i, p := range points {
+			all[i] = Neighbor{Index: i, Distance: vecNorm(Vector3{X: p.X - center.X, Y: p.Y - center.Y, Z: p.Z - center.Z})}
+		}
+		sort.Slice(all, func(a, b int) bool { return neighborLess(all[a], all[b]) })
+		return all
+	}
+
+	for q := 0; q < 20; q++ {
+		center := Vector3{X: rng.Float64()*16000 - 8000, Y: rng.Float64()*16000 - 8000, Z: rng.Float64()*4000 - 2000}
+		if q == 0 {
+			center = points[5]
+		}
+		all := bruteForce(center)
+
+		t.Run("Range", func(t *testing.T) {
+			var expected []int
+			for _, n := range all {
+				if n.Distance <= 2000 {
+					expected = append(expected, n.Index)
+				}
+			}
+			sort.Ints(expected)
+			got := tree.Range(center, 2000)
+			if len(got) != len(expected) {
+				t.Fatalf("Expected %d points; but got %d", len(expected), len(got))
+			}
+			for i := range got {
+				if got[i].Index != expected[i] {
+					t.Errorf("Expected %d; but got %d", expected[i], got[i].Index)
+				}
+			}
+		})
+
+		t.Run("Nearest", func(t *testing.T) {
+			got := tree.Nearest(center, 7)
+			if len(got) != 7 {
+				t.Fatalf("Expected 7 neighbours; but got %d", len(got))
+			}
+			for i := range got {
+				if got[i] != all[i] {
+					t.Errorf("Expected %v; but got %v", all[i], got[i])
+				}
+			}
+		})
+
+		t.Run("NearestFunc", func(t *testing.T) {
+			odd := func(i int) bool { return i%2 == 1 }
+			got := tree.NearestFunc(center, 3, odd)
+			var expected []Neighbor
+			for _, n := range all {
+				if odd(n.Index) && len(expected) < 3 {
+					expected = append(expected, n)
+				}
+			}
+			for i := range expected {
+				if got[i] != expected[i] {
+					t.Errorf("Expected %v; but got %v", expected[i], got[i])
+				}
+			}
+		})
+
+		t.Run("NearestOther", func(t *testing.T) {
+			// labels in runs of index, as left by a spanning forest under construction
+			labels := make([]int, len(points))
+			for i := range labels {
+				labels[i] = i / 100
+			}
+			own := labels[all[0].Index]
+			for _, maxDist := range []float64{math.Inf(1), 1500} {
+				expected, found := Neighbor{}, false
+				for _, n := range all {
+					if labels[n.Index] != own && n.Distance <= maxDist {
+						expected, found = n, true
+						break
+					}
+				}
+				got, ok := tree.Label(labels).NearestOther(center, own, maxDist)
+				if ok != found || got != expected && found {
+					t.Errorf("Expected %v (%v); but got %v (%v)", expected, found, got, ok)
+				}
+			}
+		})
+	}
+
+	if got := NewKDTree(points[:2]).Nearest(Vector3{}, 5); len(got) != 2 {
+		t.Errorf("Expected 2 neighbours; but got %d", len(got))
+	}
+	if got := NewKDTree(nil).Range(Vector3{}, 1e9); len(got) != 0 {
+		t.Errorf("Expected no points; but got %v", got)
+	}
+}
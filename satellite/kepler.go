@@ -0,0 +1,235 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+)
+
+// Eccentricity and inclination below this are treated as circular and equatorial
+const keplerSmall float64 = 1e-10
+
+// Errors of the orbital element conversions
+var (
+	ErrParabolicOrbit  = errors.New("parabolic orbit has no semi-major axis")
+	ErrDegenerateOrbit = errors.New("state has no angular momentum")
+	ErrTrueAnomaly     = errors.New("true anomaly is beyond the asymptotes of the hyperbola")
+)
+
+// Classical osculating orbital elements. Lengths in km, angles in radians. Hyperbolic orbits have a negative
+// semi-major axis and e > 1.
+// Undefined angles are set to zero and the remaining angle absorbs them: circular orbits have ArgPerigee 0 and
+// TrueAnomaly holds the argument of latitude, equatorial orbits have RAAN 0 and ArgPerigee holds the longitude
+// of perigee, circular equatorial orbits hold the true longitude in TrueAnomaly.
+type KeplerElements struct {
+	SemiMajorAxis float64
+	Eccentricity  float64
+	Inclination   float64
+	RAAN          float64 // right ascension of the ascending node
+	ArgPerigee    float64
+	TrueAnomaly   float64
+}
+
+// Converts an ECI position (km) and velocity (km/s) into osculating elements, using mu of the gravity model
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, algorithm 9 (rv2coe)
+func StateToKepler(r, v Vector3, grav Gravity) (el KeplerElements, err error) {
+	mu := getGravConst(grav).mu
+	magr := vecNorm(r)
+	magv := vecNorm(v)
+
+	h := vecCross(r, v)
+	magh := vecNorm(h)
+	if magr == 0 || magh == 0 {
+		return el, ErrDegenerateOrbit
+	}
+	node := Vector3{X: -h.Y, Y: h.X}
+	magn := vecNorm(node)
+
+	rdotv := vecDot(r, v)
+	c1 := magv*magv - mu/magr
+	ecc := Vector3{X: (c1*r.X - rdotv*v.X) / mu, Y: (c1*r.Y - rdotv*v.Y) / mu, Z: (c1*r.Z - rdotv*v.Z) / mu}
+	el.Eccentricity = vecNorm(ecc)
+
+	energy := magv*magv/2.0 - mu/magr
+	if math.Abs(energy) < keplerSmall || math.Abs(el.Eccentricity-1.0) < keplerSmall {
+		return el, ErrParabolicOrbit
+	}
+	el.SemiMajorAxis = -mu / (2.0 * energy)
+	el.Inclination = safeAcos(h.Z / magh)
+
+	circular := el.Eccentricity < keplerSmall
+	equatorial := el.Inclination < keplerSmall || math.Abs(el.Inclination-math.Pi) < keplerSmall
+	retrograde := el.Inclination > math.Pi/2
+
+	switch {
+	case circular && equatorial:
+		// true longitude, measured in the direction of motion
+		el.TrueAnomaly = angleFromX(r.X/magr, r.Y)
+		if retrograde {
+			el.TrueAnomaly = TWOPI - el.TrueAnomaly
+		}
+	case circular:
+		el.RAAN = angleFromX(node.X/magn, node.Y)
+		// argument of latitude
+		el.TrueAnomaly = safeAcos(vecDot(node, r) / (magn * magr))
+		if r.Z < 0 {
+			el.TrueAnomaly = TWOPI - el.TrueAnomaly
+		}
+	case equatorial:
+		// longitude of perigee
+		el.ArgPerigee = angleFromX(ecc.X/el.Eccentricity, ecc.Y)
+		if retrograde {
+			el.ArgPerigee = TWOPI - el.ArgPerigee
+		}
+		el.TrueAnomaly = trueAnomaly(ecc, el.Eccentricity, r, magr, rdotv)
+	default:
+		el.RAAN = angleFromX(node.X/magn, node.Y)
+		el.ArgPerigee = safeAcos(vecDot(node, ecc) / (magn * el.Eccentricity))
+		if ecc.Z < 0 {
+			el.ArgPerigee = TWOPI - el.ArgPerigee
+		}
+		el.TrueAnomaly = trueAnomaly(ecc, el.Eccentricity, r, magr, rdotv)
+	}
+	return el, nil
+}
+
+// Converts osculating elements into an ECI position (km) and velocity (km/s), using mu of the gravity model
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, algorithm 10 (coe2rv)
+func KeplerToState(el KeplerElements, grav Gravity) (r, v Vector3, err error) {
+	mu := getGravConst(grav).mu
+	if math.Abs(el.Eccentricity-1.0) < keplerSmall {
+		return r, v, ErrParabolicOrbit
+	}
+	p := el.SemiMajorAxis * (1.0 - el.Eccentricity*el.Eccentricity)
+	if p <= 0 {
+		return r, v, ErrDegenerateOrbit
+	}
+
+	sinNu, cosNu := math.Sincos(el.TrueAnomaly)
+	denom := 1.0 + el.Eccentricity*cosNu
+	if denom <= 0 {
+		return r, v, ErrTrueAnomaly
+	}
+	rpqw := Vector3{X: p * cosNu / denom, Y: p * sinNu / denom}
+	vpqw := Vector3{X: -math.Sqrt(mu/p) * sinNu, Y: math.Sqrt(mu/p) * (el.Eccentricity + cosNu)}
+
+	// perifocal to ECI
+	pqw := rot3(-el.RAAN).mulm(rot1(-el.Inclination)).mulm(rot3(-el.ArgPerigee))
+	return pqw.mul(rpqw), pqw.mul(vpqw), nil
+}
+
+// Converts a true anomaly into the mean anomaly (radians) for an elliptic or hyperbolic orbit.
+// Elliptic mean anomalies are within [0, 2pi).
+func TrueToMeanAnomaly(nu, e float64) float64 {
+	if e < 1.0 {
+		sinNu, cosNu := math.Sincos(nu)
+		ea := math.Atan2(math.Sqrt(1.0-e*e)*sinNu, e+cosNu)
+		m := math.Mod(ea-e*math.Sin(ea), TWOPI)
+		if m < 0 {
+			m += TWOPI
+		}
+		return m
+	}
+	h := 2.0 * math.Atanh(math.Sqrt((e-1.0)/(e+1.0))*math.Tan(nu/2.0))
+	return e*math.Sinh(h) - h
+}
+
+// Converts a mean anomaly into the true anomaly (radians) by solving Kepler's equation with Newton's method.
+// Elliptic true anomalies are within [0, 2pi).
+func MeanToTrueAnomaly(m, e float64) float64 {
+	if e < 1.0 {
+		m = math.Mod(m, TWOPI)
+		ea := m
+		if e > 0.8 {
+			ea = math.Pi
+		}
+		for i := 0; i < 50; i++ {
+			step := (ea - e*math.Sin(ea) - m) / (1.0 - e*math.Cos(ea))
+			ea -= step
+			if math.Abs(step) < 1e-14 {
+				break
+			}
+		}
+		sinE, cosE := math.Sincos(ea)
+		nu := math.Atan2(math.Sqrt(1.0-e*e)*sinE, cosE-e)
+		if nu < 0 {
+			nu += TWOPI
+		}
+		return nu
+	}
+
+	h := math.Asinh(m / e)
+	for i := 0; i < 50; i++ {
+		step := (e*math.Sinh(h) - h - m) / (e*math.Cosh(h) - 1.0)
+		h -= step
+		if math.Abs(step) < 1e-14 {
+			break
+		}
+	}
+	return 2.0 * math.Atan(math.Sqrt((e+1.0)/(e-1.0))*math.Tanh(h/2.0))
+}
+
+// Returns the mean anomaly of the elements (radians)
+func (el KeplerElements) MeanAnomaly() float64 {
+	return TrueToMeanAnomaly(el.TrueAnomaly, el.Eccentricity)
+}
+
+// Returns the orbital period in seconds, +Inf for hyperbolic orbits
+func (el KeplerElements) Period(grav Gravity) float64 {
+	if el.SemiMajorAxis <= 0 {
+		return math.Inf(1)
+	}
+	return TWOPI * math.Sqrt(el.SemiMajorAxis*el.SemiMajorAxis*el.SemiMajorAxis/getGravConst(grav).mu)
+}
+
+// Returns the apogee altitude above the equatorial radius of the gravity model (km), +Inf for hyperbolic orbits
+func (el KeplerElements) ApogeeAltitude(grav Gravity) float64 {
+	if el.SemiMajorAxis <= 0 {
+		return math.Inf(1)
+	}
+	return el.SemiMajorAxis*(1.0+el.Eccentricity) - getGravConst(grav).radiusearthkm
+}
+
+// Returns the perigee altitude above the equatorial radius of the gravity model (km)
+func (el KeplerElements) PerigeeAltitude(grav Gravity) float64 {
+	return el.SemiMajorAxis*(1.0-el.Eccentricity) - getGravConst(grav).radiusearthkm
+}
+
+// Returns the specific orbital energy (km^2/s^2), negative for bound orbits
+func (el KeplerElements) SpecificEnergy(grav Gravity) float64 {
+	return -getGravConst(grav).mu / (2.0 * el.SemiMajorAxis)
+}
+
+// Angle between the eccentricity vector and the position, beyond pi when moving towards perigee
+func trueAnomaly(ecc Vector3, e float64, r Vector3, magr, rdotv float64) float64 {
+	nu := safeAcos(vecDot(ecc, r) / (e * magr))
+	if rdotv < 0 {
+		nu = TWOPI - nu
+	}
+	return nu
+}
+
+// Returns the angle of a direction in the xy plane from its x direction cosine and the sign of y, within [0, 2pi)
+func angleFromX(cosAngle, y float64) float64 {
+	angle := safeAcos(cosAngle)
+	if y < 0 {
+		angle = TWOPI - angle
+	}
+	return angle
+}
+
+// Acos clamped against rounding just outside [-1, 1]
+func safeAcos(x float64) float64 {
+	return math.Acos(math.Max(-1.0, math.Min(1.0, x)))
+}
+
+func vecDot(a, b Vector3) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func vecCross(a, b Vector3) Vector3 {
+	return Vector3{X: a.Y*b.Z - a.Z*b.Y, Y: a.Z*b.X - a.X*b.Z, Z: a.X*b.Y - a.Y*b.X}
+}
+
+func vecNorm(a Vector3) float64 {
+	return math.Sqrt(a.X*a.X + a.Y*a.Y + a.Z*a.Z)
+}
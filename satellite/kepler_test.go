@@ -0,0 +1,106 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestStateToKepler(t *testing.T) {
+	// Vallado, Fundamentals of Astrodynamics and Applications, example 2-5
+	r := Vector3{X: 6524.834, Y: 6862.875, Z: 6448.296}
+	v := Vector3{X: 4.901327, Y: 5.533756, Z: -1.976341}
+	el, err := StateToKepler(r, v, GravityWGS84)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := []struct {
+		name          string
+		got, expected float64
+		tol           float64
+	}{
+		{"a", el.SemiMajorAxis, 36127.343, 0.1},
+		{"e", el.Eccentricity, 0.832853, 1e-5},
+		{"i", el.Inclination * RAD2DEG, 87.870, 1e-3},
+		{"RAAN", el.RAAN * RAD2DEG, 227.898, 1e-3},
+		{"argp", el.ArgPerigee * RAD2DEG, 53.38, 1e-2},
+		{"nu", el.TrueAnomaly * RAD2DEG, 92.335, 1e-3},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.expected) > c.tol {
+			t.Errorf("%s: Expected %f; but got %f", c.name, c.expected, c.got)
+		}
+	}
+}
+
+func TestKeplerRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		el   KeplerElements
+	}{
+		{"Elliptic", KeplerElements{SemiMajorAxis: 26600, Eccentricity: 0.7, Inclination: 63.4 * DEG2RAD, RAAN: 1.2, ArgPerigee: 4.7, TrueAnomaly: 2.1}},
+		{"Circular", KeplerElements{SemiMajorAxis: 6928, Inclination: 97.6 * DEG2RAD, RAAN: 5.5, TrueAnomaly: 4.0}},
+		{"Equatorial", KeplerElements{SemiMajorAxis: 24400, Eccentricity: 0.73, ArgPerigee: 3.3, TrueAnomaly: 0.4}},
+		{"Retrograde equatorial", KeplerElements{SemiMajorAxis: 24400, Eccentricity: 0.73, Inclination: math.Pi, ArgPerigee: 3.3, TrueAnomaly: 0.4}},
+		{"Circular equatorial", KeplerElements{SemiMajorAxis: 42164, TrueAnomaly: 1.9}},
+		{"Hyperbolic", KeplerElements{SemiMajorAxis: -20000, Eccentricity: 1.4, Inclination: 0.5, RAAN: 2.0, ArgPerigee: 1.0, TrueAnomaly: -0.8}},
+	}
+	angle := func(a, b float64) float64 { return math.Abs(math.Remainder(a-b, TWOPI)) }
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r, v, err := KeplerToState(c.el, GravityWGS84)
+			if err != nil {
+				t.Fatal(err)
+			}
+			el, err := StateToKepler(r, v, GravityWGS84)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(el.SemiMajorAxis-c.el.SemiMajorAxis) > 1e-6 || math.Abs(el.Eccentricity-c.el.Eccentricity) > 1e-9 {
+				t.Errorf("Expected %+v; but got %+v", c.el, el)
+			}
+			if angle(el.Inclination, c.el.Inclination) > 1e-9 || angle(el.RAAN, c.el.RAAN) > 1e-9 ||
+				angle(el.ArgPerigee, c.el.ArgPerigee) > 1e-9 || angle(el.TrueAnomaly, c.el.TrueAnomaly) > 1e-9 {
+				t.Errorf("Expected %+v; but got %+v", c.el, el)
+			}
+		})
+	}
+	t.Run("Parabolic", func(t *testing.T) {
+		if _, _, err := KeplerToState(KeplerElements{SemiMajorAxis: 7000, Eccentricity: 1}, GravityWGS84); !errors.Is(err, ErrParabolicOrbit) {
+			t.Errorf("Expected %v; but got %v", ErrParabolicOrbit, err)
+		}
+	})
+}
+
+func TestAnomalies(t *testing.T) {
+	for _, e := range []float64{0, 0.01, 0.5, 0.95, 1.5, 3.0} {
+		for _, nu := range []float64{0.1, 1.0, 2.0, 2.5, 4.0, 6.0} {
+			if e > 1 && 1+e*math.Cos(nu) <= 0 {
+				continue
+			}
+			got := MeanToTrueAnomaly(TrueToMeanAnomaly(nu, e), e)
+			if math.Abs(math.Remainder(got-nu, TWOPI)) > 1e-10 {
+				t.Errorf("e=%f: Expected %f; but got %f", e, nu, got)
+			}
+		}
+	}
+}
+
+func TestKeplerDerived(t *testing.T) {
+	el := KeplerElements{SemiMajorAxis: 26560, Eccentricity: 0.01}
+	period := el.Period(GravityWGS84)
+	if math.Abs(period-43077.7) > 1 {
+		t.Errorf("Expected %f; but got %f", 43077.7, period)
+	}
+	if expected := 26560*1.01 - 6378.137; math.Abs(el.ApogeeAltitude(GravityWGS84)-expected) > 1e-9 {
+		t.Errorf("Expected %f; but got %f", expected, el.ApogeeAltitude(GravityWGS84))
+	}
+	if expected := 26560*0.99 - 6378.137; math.Abs(el.PerigeeAltitude(GravityWGS84)-expected) > 1e-9 {
+		t.Errorf("Expected %f; but got %f", expected, el.PerigeeAltitude(GravityWGS84))
+	}
+	r, v, _ := KeplerToState(el, GravityWGS84)
+	energy := vecNorm(v)*vecNorm(v)/2 - 398600.5/vecNorm(r)
+	if math.Abs(el.SpecificEnergy(GravityWGS84)-energy) > 1e-9 {
+		t.Errorf("Expected %f; but got %f", energy, el.SpecificEnergy(GravityWGS84))
+	}
+}
@@ -0,0 +1,265 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Options of the numerical propagator
+type NumericalOptions struct {
+	Gravity              Gravity       // mu, Earth radius and zonal coefficients, defaults to GravityWGS84
+	Zonals               int           // highest zonal harmonic included (2 to 4), lower values integrate the two-body problem
+	BallisticCoefficient float64       // Cd*A/m in m^2/kg for exponential atmosphere drag, zero disables drag
+	Step                 time.Duration // fixed RK4 step, defaults to 10 s
+}
+
+// NumericalPropagator integrates the equations of motion from an initial ECI state with a fixed step fourth
+// order Runge-Kutta scheme. The integration always runs on the grid of Step from the epoch, with a last partial
+// step to the requested instant, so the state at t does not depend on earlier calls. The states on the grid are
+// cached (the last one reached and every numericalCheckpointSteps-th), so propagating forward in small steps is
+// cheap and going back does not restart from the epoch. It is safe for concurrent use.
+type NumericalPropagator struct {
+	epoch time.Time
+	opts  NumericalOptions
+	grav  GravConst
+
+	mu          sync.Mutex
+	checkpoints [2][]numericalState // every numericalCheckpointSteps-th grid state after [0] and before [1] the epoch
+	lastK       int64               // grid index (steps from the epoch, negative before it) of last
+	last        numericalState
+}
+
+// Grid states kept by NumericalPropagator, in steps
+const numericalCheckpointSteps = 64
+
+type numericalState struct {
+	r, v Vector3
+}
+
+// Creates a numerical propagator from a position (km) and velocity (km/s) at epoch
+func NewNumericalPropagator(epoch time.Time, pos, vel Vector3, opts NumericalOptions) (*NumericalPropagator, error) {
+	if opts.Gravity == "" {
+		opts.Gravity = GravityWGS84
+	}
+	if opts.Step == 0 {
+		opts.Step = 10 * time.Second
+	}
+	if opts.Step < 0 {
+		return nil, errors.New("numerical propagator step must be positive")
+	}
+	if opts.Zonals > 4 {
+		return nil, fmt.Errorf("zonal harmonics up to J4 are supported, not J%d", opts.Zonals)
+	}
+	if opts.BallisticCoefficient < 0 {
+		return nil, errors.New("ballistic coefficient must not be negative")
+	}
+	if vecNorm(pos) == 0 {
+		return nil, ErrDegenerateOrbit
+	}
+	start := numericalState{r: pos, v: vel}
+	return &NumericalPropagator{
+		epoch: epoch, opts: opts, grav: getGravConst(opts.Gravity),
+		checkpoints: [2][]numericalState{{start}, {start}}, last: start,
+	}, nil
+}
+
+// Creates a numerical propagator from osculating elements at epoch, see KeplerToState
+func NewNumericalPropagatorFromKepler(epoch time.Time, el KeplerElements, opts NumericalOptions) (*NumericalPropagator, error) {
+	if opts.Gravity == "" {
+		opts.Gravity = GravityWGS84
+	}
+	pos, vel, err := KeplerToState(el, opts.Gravity)
+	if err != nil {
+		return nil, err
+	}
+	return NewNumericalPropagator(epoch, pos, vel, opts)
+}
+
+// Returns the epoch of the initial state
+func (p *NumericalPropagator) Epoch() time.Time {
+	return p.epoch
+}
+
+// Integrates the state to t. Returns an error wrapping ErrDecayed if the trajectory enters the Earth.
+func (p *NumericalPropagator) Propagate(t time.Time) (pos, vel Vector3, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	target := t.Sub(p.epoch)
+	k := int64(target / p.opts.Step)
+	s, err := p.gridState(k)
+	if err != nil {
+		return Vector3{}, Vector3{}, err
+	}
+	if rest := target - time.Duration(k)*p.opts.Step; rest != 0 {
+		var above bool
+		if s.r, s.v, above = p.rk4(s.r, s.v, rest.Seconds()); !above {
+			return Vector3{}, Vector3{}, fmt.Errorf("numerical propagation at %v: %w", t, ErrDecayed)
+		}
+	}
+	return s.r, s.v, nil
+}
+
+// Returns the state k steps from the epoch, integrating from the nearest cached grid state between the epoch
+// and k
+func (p *NumericalPropagator) gridState(k int64) (numericalState, error) {
+	dir, side := int64(1), 0
+	if k < 0 {
+		dir, side = -1, 1
+	}
+	j := k * dir / numericalCheckpointSteps
+	if n := int64(len(p.checkpoints[side])); j >= n {
+		j = n - 1
+	}
+	i, s := j*numericalCheckpointSteps*dir, p.checkpoints[side][j]
+	if p.lastK*dir > i*dir && p.lastK*dir <= k*dir {
+		i, s = p.lastK, p.last
+	}
+
+	h := float64(dir) * p.opts.Step.Seconds()
+	for i != k {
+		var above bool
+		s.r, s.v, above = p.rk4(s.r, s.v, h)
+		i += dir
+		if !above {
+			return numericalState{}, fmt.Errorf("numerical propagation at %v: %w", p.epoch.Add(time.Duration(i)*p.opts.Step), ErrDecayed)
+		}
+		if i*dir%numericalCheckpointSteps == 0 && i*dir/numericalCheckpointSteps == int64(len(p.checkpoints[side])) {
+			p.checkpoints[side] = append(p.checkpoints[side], s)
+		}
+	}
+	p.lastK, p.last = k, s
+	return s, nil
+}
+
+// One Runge-Kutta step of h seconds. above is false if the step entered the Earth, in which case the
+// state is meaningless (the dense lower atmosphere makes the step unstable long before the surface).
+func (p *NumericalPropagator) rk4(r, v Vector3, h float64) (rn, vn Vector3, above bool) {
+	add := func(a, b Vector3, s float64) Vector3 { return Vector3{X: a.X + s*b.X, Y: a.Y + s*b.Y, Z: a.Z + s*b.Z} }
+	above = true
+	stage := func(r, v Vector3) Vector3 {
+		if vecNorm(r) < p.grav.radiusearthkm {
+			above = false
+		}
+		return p.acceleration(r, v)
+	}
+
+	k1v := stage(r, v)
+	k1r := v
+	k2v := stage(add(r, k1r, h/2), add(v, k1v, h/2))
+	k2r := add(v, k1v, h/2)
+	k3v := stage(add(r, k2r, h/2), add(v, k2v, h/2))
+	k3r := add(v, k2v, h/2)
+	k4v := stage(add(r, k3r, h), add(v, k3v, h))
+	k4r := add(v, k3v, h)
+
+	rn = add(r, add(add(k1r, k4r, 1), add(k2r, k3r, 1), 2), h/6)
+	vn = add(v, add(add(k1v, k4v, 1), add(k2v, k3v, 1), 2), h/6)
+	if vecNorm(rn) < p.grav.radiusearthkm {
+		above = false
+	}
+	return
+}
+
+// Acceleration (km/s^2) of the two-body problem with the configured zonal harmonics and drag
+func (p *NumericalPropagator) acceleration(r, v Vector3) Vector3 {
+	a := gravityAcceleration(r, p.grav, p.opts.Zonals)
+	if p.opts.BallisticCoefficient > 0 {
+		d := dragAcceleration(r, v, p.grav.radiusearthkm, p.opts.BallisticCoefficient)
+		a.X += d.X
+		a.Y += d.Y
+		a.Z += d.Z
+	}
+	return a
+}
+
+// Acceleration (km/s^2) of the central body with zonal harmonics up to J<zonals>
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, section 8.6.1
+func gravityAcceleration(r Vector3, grav GravConst, zonals int) Vector3 {
+	r2 := r.X*r.X + r.Y*r.Y + r.Z*r.Z
+	mag := math.Sqrt(r2)
+	mu := grav.mu
+	re := grav.radiusearthkm
+
+	c := -mu / (r2 * mag)
+	a := Vector3{X: c * r.X, Y: c * r.Y, Z: c * r.Z}
+
+	z2 := r.Z * r.Z / r2 // sin^2 of the geocentric latitude
+	if zonals >= 2 {
+		c := -1.5 * grav.j2 * mu * re * re / (r2 * r2 * mag)
+		a.X += c * r.X * (1.0 - 5.0*z2)
+		a.Y += c * r.Y * (1.0 - 5.0*z2)
+		a.Z += c * r.Z * (3.0 - 5.0*z2)
+	}
+	if zonals >= 3 {
+		c := -2.5 * grav.j3 * mu * re * re * re / (r2 * r2 * r2 * mag)
+		a.X += c * r.X * r.Z * (3.0 - 7.0*z2)
+		a.Y += c * r.Y * r.Z * (3.0 - 7.0*z2)
+		a.Z += c * r2 * (6.0*z2 - 7.0*z2*z2 - 0.6)
+	}
+	if zonals >= 4 {
+		c := 1.875 * grav.j4 * mu * re * re * re * re / (r2 * r2 * r2 * mag)
+		a.X += c * r.X * (1.0 - 14.0*z2 + 21.0*z2*z2)
+		a.Y += c * r.Y * (1.0 - 14.0*z2 + 21.0*z2*z2)
+		a.Z += c * r.Z * (5.0 - 70.0/3.0*z2 + 21.0*z2*z2)
+	}
+	return a
+}
+
+// Reference densities of the exponential atmosphere: base altitude (km), density (kg/m^3) and scale height (km)
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, table 8-4
+var exponentialAtmosphere = [...][3]float64{
+	{0, 1.225, 7.249},
+	{25, 3.899e-2, 6.349},
+	{30, 1.774e-2, 6.682},
+	{40, 3.972e-3, 7.554},
+	{50, 1.057e-3, 8.382},
+	{60, 3.206e-4, 7.714},
+	{70, 8.770e-5, 6.549},
+	{80, 1.905e-5, 5.799},
+	{90, 3.396e-6, 5.382},
+	{100, 5.297e-7, 5.877},
+	{110, 9.661e-8, 7.263},
+	{120, 2.438e-8, 9.473},
+	{130, 8.484e-9, 12.636},
+	{140, 3.845e-9, 16.149},
+	{150, 2.070e-9, 22.523},
+	{180, 5.464e-10, 29.740},
+	{200, 2.789e-10, 37.105},
+	{250, 7.248e-11, 45.546},
+	{300, 2.418e-11, 53.628},
+	{350, 9.518e-12, 53.298},
+	{400, 3.725e-12, 58.515},
+	{450, 1.585e-12, 60.828},
+	{500, 6.967e-13, 63.822},
+	{600, 1.454e-13, 71.835},
+	{700, 3.614e-14, 88.667},
+	{800, 1.170e-14, 124.64},
+	{900, 5.245e-15, 181.05},
+	{1000, 3.019e-15, 268.00},
+}
+
+// Atmospheric density (kg/m^3) at an altitude (km) above a spherical Earth
+func atmosphereDensity(alt float64) float64 {
+	if alt < 0 {
+		alt = 0
+	}
+	i := len(exponentialAtmosphere) - 1
+	for i > 0 && alt < exponentialAtmosphere[i][0] {
+		i--
+	}
+	base := exponentialAtmosphere[i]
+	return base[1] * math.Exp(-(alt-base[0])/base[2])
+}
+
+// Drag acceleration (km/s^2) in an atmosphere rotating with the Earth. bc is Cd*A/m in m^2/kg.
+func dragAcceleration(r, v Vector3, re, bc float64) Vector3 {
+	rho := atmosphereDensity(vecNorm(r) - re)
+	vrel := Vector3{X: v.X + earthRotationRate*r.Y, Y: v.Y - earthRotationRate*r.X, Z: v.Z}
+	// rho [kg/m^3] * bc [m^2/kg] * v^2 [km^2/s^2] is in km^2/(s^2 m), times 1000 for km/s^2
+	c := -0.5 * bc * rho * vecNorm(vrel) * 1000.0
+	return Vector3{X: c * vrel.X, Y: c * vrel.Y, Z: c * vrel.Z}
+}
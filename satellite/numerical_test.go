@@ -0,0 +1,148 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGravityAcceleration(t *testing.T) {
+	// the acceleration must be the gradient of the zonal potential
+	grav := getGravConst(GravityWGS84)
+	potential := func(r Vector3) float64 {
+		mag := vecNorm(r)
+		s := r.Z / mag
+		q := grav.radiusearthkm / mag
+		p2 := (3*s*s - 1) / 2
+		p3 := (5*s*s*s - 3*s) / 2
+		p4 := (35*s*s*s*s - 30*s*s + 3) / 8
+		return grav.mu / mag * (1 - grav.j2*q*q*p2 - grav.j3*q*q*q*p3 - grav.j4*q*q*q*q*p4)
+	}
+	r := Vector3{X: 4000, Y: -3000, Z: 5000}
+	h := 1e-2
+	expected := Vector3{
+		X: (potential(Vector3{X: r.X + h, Y: r.Y, Z: r.Z}) - potential(Vector3{X: r.X - h, Y: r.Y, Z: r.Z})) / (2 * h),
+		Y: (potential(Vector3{X: r.X, Y: r.Y + h, Z: r.Z}) - potential(Vector3{X: r.X, Y: r.Y - h, Z: r.Z})) / (2 * h),
+		Z: (potential(Vector3{X: r.X, Y: r.Y, Z: r.Z + h}) - potential(Vector3{X: r.X, Y: r.Y, Z: r.Z - h})) / (2 * h),
+	}
+	a := gravityAcceleration(r, grav, 4)
+	if math.Abs(a.X-expected.X) > 1e-11 || math.Abs(a.Y-expected.Y) > 1e-11 || math.Abs(a.Z-expected.Z) > 1e-11 {
+		t.Errorf("Expected %v; but got %v", expected, a)
+	}
+}
+
+func TestNumericalPropagator(t *testing.T) {
+	epoch := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	el := KeplerElements{SemiMajorAxis: 7000, Eccentricity: 0.01, Inclination: 51.6 * DEG2RAD, RAAN: 1.0, ArgPerigee: 2.0, TrueAnomaly: 0.5}
+
+	t.Run("Two-body period", func(t *testing.T) {
+		prop, err := NewNumericalPropagatorFromKepler(epoch, el, NumericalOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		period := time.Duration(el.Period(GravityWGS84) * 1e9)
+		r0, _, _ := prop.Propagate(epoch)
+		r1, _, err := prop.Propagate(epoch.Add(period))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := vecNorm(Vector3{X: r1.X - r0.X, Y: r1.Y - r0.Y, Z: r1.Z - r0.Z}); d > 1e-4 {
+			t.Errorf("Expected %f; but got %f", 0.0, d)
+		}
+	})
+	t.Run("J2 nodal regression", func(t *testing.T) {
+		prop, err := NewNumericalPropagatorFromKepler(epoch, el, NumericalOptions{Zonals: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		grav := getGravConst(GravityWGS84)
+		n := math.Sqrt(grav.mu / (el.SemiMajorAxis * el.SemiMajorAxis * el.SemiMajorAxis))
+		p := el.SemiMajorAxis * (1 - el.Eccentricity*el.Eccentricity)
+		rate := -1.5 * n * grav.j2 * (grav.radiusearthkm / p) * (grav.radiusearthkm / p) * math.Cos(el.Inclination)
+
+		days := 10.0
+		r, v, err := prop.Propagate(epoch.Add(time.Duration(days * 86400e9)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		osc, _ := StateToKepler(r, v, GravityWGS84)
+		// the osculating node oscillates around the secular drift by a few 0.01 deg
+		expected := math.Mod(el.RAAN+rate*days*86400+TWOPI, TWOPI)
+		if math.Abs(osc.RAAN-expected) > 0.1*DEG2RAD {
+			t.Errorf("Expected %f; but got %f", expected*RAD2DEG, osc.RAAN*RAD2DEG)
+		}
+	})
+	t.Run("Independent of earlier calls", func(t *testing.T) {
+		prop, _ := NewNumericalPropagatorFromKepler(epoch, el, NumericalOptions{Zonals: 4})
+		for _, d := range []time.Duration{3 * time.Hour, -time.Hour - 7*time.Second, 11 * time.Minute, 3*time.Hour + time.Second} {
+			// back and forth around the target and between the grid points, as the conjunction refinement does
+			for _, before := range []time.Duration{2 * d, d / 3, d/2 + 3*time.Second} {
+				prop.Propagate(epoch.Add(before))
+			}
+			fresh, _ := NewNumericalPropagatorFromKepler(epoch, el, NumericalOptions{Zonals: 4})
+			r1, v1, _ := prop.Propagate(epoch.Add(d))
+			r2, v2, _ := fresh.Propagate(epoch.Add(d))
+			if r1 != r2 || v1 != v2 {
+				t.Errorf("Expected %v; but got %v", r2, r1)
+			}
+		}
+	})
+	t.Run("Drag", func(t *testing.T) {
+		low := KeplerElements{SemiMajorAxis: 6378.137 + 300, Inclination: 51.6 * DEG2RAD}
+		prop, _ := NewNumericalPropagatorFromKepler(epoch, low, NumericalOptions{Zonals: 2, BallisticCoefficient: 0.01})
+		r, v, err := prop.Propagate(epoch.Add(24 * time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if osc, _ := StateToKepler(r, v, GravityWGS84); osc.SemiMajorAxis > low.SemiMajorAxis-0.1 {
+			t.Errorf("Expected the orbit to decay; but got a = %f", osc.SemiMajorAxis)
+		}
+
+		reentry, _ := NewNumericalPropagatorFromKepler(epoch, KeplerElements{SemiMajorAxis: 6378.137 + 90}, NumericalOptions{BallisticCoefficient: 0.02})
+		if _, _, err := reentry.Propagate(epoch.Add(24 * time.Hour)); !errors.Is(err, ErrDecayed) {
+			t.Errorf("Expected %v; but got %v", ErrDecayed, err)
+		}
+	})
+	t.Run("Options", func(t *testing.T) {
+		if _, err := NewNumericalPropagator(epoch, Vector3{X: 7000}, Vector3{Y: 7.5}, NumericalOptions{Zonals: 5}); err == nil {
+			t.Errorf("Expected an error for J5")
+		}
+	})
+}
+
+func TestPropagatorCatalog(t *testing.T) {
+	// a TLE satellite and a numerically propagated one side by side
+	epoch := time.Date(2022, 6, 10, 0, 0, 0, 0, time.UTC)
+	sgp4 := SimpleSatellite{Name: "CALSPHERE 1",
+		Ole1: "1 00900U 64063C   22160.52204282  .00000408  00000+0  42495-3 0  9992",
+		Ole2: "2 00900  90.1760  40.7701 0029467  47.9267  23.7177 13.73809888869573"}
+	if err := InitSat(&sgp4, epoch); err != nil {
+		t.Fatal(err)
+	}
+	prop, err := NewNumericalPropagator(epoch, sgp4.Position, sgp4.Velocity, NumericalOptions{Zonals: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cat := Catalog{sgp4, NewSimpleSatellite("SYNTHETIC", prop)}
+
+	eph, err := PropagateCatalogEphemeris(context.Background(), cat, epoch, epoch.Add(90*time.Minute), time.Minute, BatchOptions{Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// starting from the same osculating state the two stay within kilometres over an orbit
+	for j := range eph.Times {
+		a, _ := eph.Position(0, j)
+		b, ok := eph.Position(1, j)
+		if d := vecNorm(Vector3{X: a.X - b.X, Y: a.Y - b.Y, Z: a.Z - b.Z}); !ok || d > 10 {
+			t.Errorf("Sample %d: expected less than %f; but got %f", j, 10.0, d)
+		}
+	}
+	if !cat[1].propagator().Epoch().Equal(epoch) {
+		t.Errorf("Expected %v; but got %v", epoch, cat[1].propagator().Epoch())
+	}
+	if expected := time.Date(2022, 6, 9, 12, 31, 44, 499648000, time.UTC); !cat[0].propagator().Epoch().Equal(expected) {
+		t.Errorf("Expected %v; but got %v", expected, cat[0].propagator().Epoch())
+	}
+}
@@ -0,0 +1,57 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// Returns the NORAD catalog number
+func (s Satellite) NoradID() int64 {
+	return s.satnum
+}
+
+// Returns the epoch of the element set in UTC, rounded to the microsecond
+func (s Satellite) Epoch() time.Time {
+	return epochTime(epochYear(s.epochyr), s.epochdays)
+}
+
+// Returns the mean motion in revolutions per day. This is the Brouwer mean motion sgp4 works with, the TLE
+// carries the slightly different Kozai mean motion (see SatToElements for the TLE value).
+func (s Satellite) MeanMotion() float64 {
+	return s.no * 1440.0 / TWOPI
+}
+
+// Returns the orbital period of the mean motion
+func (s Satellite) Period() time.Duration {
+	return time.Duration(math.Round(TWOPI / s.no * 60e9))
+}
+
+// Returns the mean semi-major axis in km
+func (s Satellite) SemiMajorAxis() float64 {
+	return (1.0 + (s.alta+s.altp)/2.0) * s.whichconst.radiusearthkm
+}
+
+// Returns the mean inclination in degrees
+func (s Satellite) Inclination() float64 {
+	return s.inclo * RAD2DEG
+}
+
+// Returns the mean eccentricity
+func (s Satellite) Eccentricity() float64 {
+	return s.ecco
+}
+
+// Returns the mean apogee altitude in km above the equatorial radius of the gravity model
+func (s Satellite) ApogeeAltitude() float64 {
+	return s.alta * s.whichconst.radiusearthkm
+}
+
+// Returns the mean perigee altitude in km above the equatorial radius of the gravity model
+func (s Satellite) PerigeeAltitude() float64 {
+	return s.altp * s.whichconst.radiusearthkm
+}
+
+// Returns the B* drag term in inverse earth radii
+func (s Satellite) BStar() float64 {
+	return s.bstar
+}
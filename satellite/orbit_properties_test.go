@@ -0,0 +1,52 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestOrbitProperties(t *testing.T) {
+	// ISS#25544
+	sat := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+		"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", GravityWGS72)
+
+	if sat.NoradID() != 25544 {
+		t.Errorf("Expected %d; but got %d", 25544, sat.NoradID())
+	}
+	if expected := time.Date(2008, 9, 20, 12, 25, 40, 104192000, time.UTC); !sat.Epoch().Equal(expected) {
+		t.Errorf("Expected %v; but got %v", expected, sat.Epoch())
+	}
+	if math.Abs(sat.Inclination()-51.6416) > 1e-9 {
+		t.Errorf("Expected %f; but got %f", 51.6416, sat.Inclination())
+	}
+	if sat.Eccentricity() != 0.0006703 {
+		t.Errorf("Expected %f; but got %f", 0.0006703, sat.Eccentricity())
+	}
+	if sat.BStar() != -0.11606e-4 {
+		t.Errorf("Expected %f; but got %f", -0.11606e-4, sat.BStar())
+	}
+	// the Brouwer mean motion differs from the TLE's Kozai value in the 4th decimal
+	if math.Abs(sat.MeanMotion()-15.72125391) > 2e-3 {
+		t.Errorf("Expected %f; but got %f", 15.72125391, sat.MeanMotion())
+	}
+	if period := sat.Period(); period < 91*time.Minute+30*time.Second || period > 91*time.Minute+40*time.Second {
+		t.Errorf("Expected about 91.6 min; but got %v", period)
+	}
+
+	// apogee and perigee follow from the semi-major axis, which Kepler's third law ties to the period
+	a := sat.SemiMajorAxis()
+	grav := getGravConst(GravityWGS72)
+	if expected := math.Cbrt(grav.mu * math.Pow(sat.Period().Seconds()/TWOPI, 2)); math.Abs(a-expected) > 1e-3 {
+		t.Errorf("Expected %f; but got %f", expected, a)
+	}
+	if expected := a*(1+sat.Eccentricity()) - grav.radiusearthkm; math.Abs(sat.ApogeeAltitude()-expected) > 1e-6 {
+		t.Errorf("Expected %f; but got %f", expected, sat.ApogeeAltitude())
+	}
+	if expected := a*(1-sat.Eccentricity()) - grav.radiusearthkm; math.Abs(sat.PerigeeAltitude()-expected) > 1e-6 {
+		t.Errorf("Expected %f; but got %f", expected, sat.PerigeeAltitude())
+	}
+	if sat.PerigeeAltitude() < 340 || sat.ApogeeAltitude() > 360 {
+		t.Errorf("Expected altitudes around 350 km; but got %f x %f", sat.PerigeeAltitude(), sat.ApogeeAltitude())
+	}
+}
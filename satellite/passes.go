@@ -0,0 +1,157 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Step of the coarse elevation scan in FindPasses. Passes that stay above the mask for less than this are
+// still found, as long as the elevation has a sampled local maximum.
+const passScanStep = 30 * time.Second
+
+// Precision of the refined AOS, TCA and LOS times
+const passPrecision = time.Millisecond
+
+// A ground station with its geodetic location on the WGS84 ellipsoid (radians, km) and the elevation mask
+// (radians) below which a satellite is not in view
+type GroundStation struct {
+	Name         string
+	Location     LatLongAlt
+	MinElevation float64
+}
+
+// One pass of a satellite over a ground station
+type Pass struct {
+	AOS          time.Time // acquisition of signal, the satellite rises above the elevation mask
+	TCA          time.Time // time of closest approach, at the maximum elevation
+	LOS          time.Time // loss of signal, the satellite sets below the elevation mask
+	MaxElevation float64   // radians
+}
+
+// Returns the look angles (radians, km) from the ground station to the satellite at t
+func (gs GroundStation) LookAngles(sat Satellite, t time.Time) (LookAngles, error) {
+	pos, _, err := PropagateTEME(sat, t)
+	if err != nil {
+		return LookAngles{}, err
+	}
+	jd, jdFrac := JDayTime(t)
+	obs := LatLong{Latitude: gs.Location.Latitude, Longitude: gs.Location.Longitude}
+	// ECIToLookAngles rotates by the GMST, which takes TEME into the Earth fixed frame
+	return ECIToLookAngles(Vector3(pos), obs, gs.Location.Altitude, jd+jdFrac), nil
+}
+
+// Finds the passes of the satellite over the ground station between start and stop, in chronological order.
+// The elevation is scanned in steps of passScanStep, rises and sets are refined by bisection and the
+// culmination by golden section search, all to passPrecision. A pass in progress at start (or stop) has its
+// AOS (or LOS) clipped to the window.
+// On a propagation error the passes found so far are returned with the error.
+func FindPasses(sat Satellite, gs GroundStation, start, stop time.Time) ([]Pass, error) {
+	if stop.Before(start) {
+		return nil, errors.New("pass window stop is before start")
+	}
+
+	var propErr error
+	elevation := func(t time.Time) float64 {
+		if propErr != nil {
+			return math.Inf(-1)
+		}
+		look, err := gs.LookAngles(sat, t)
+		if err != nil {
+			propErr = err
+			return math.Inf(-1)
+		}
+		return look.El - gs.MinElevation
+	}
+
+	var passes []Pass
+	var aos time.Time
+	inPass := false
+
+	t0 := start
+	e0 := elevation(t0)
+	if e0 >= 0 {
+		aos, inPass = start, true
+	}
+	var tPrev time.Time
+	ePrev := math.Inf(-1)
+	for t0.Before(stop) && propErr == nil {
+		t1 := t0.Add(passScanStep)
+		if t1.After(stop) {
+			t1 = stop
+		}
+		e1 := elevation(t1)
+		if propErr != nil {
+			break
+		}
+
+		switch {
+		case !inPass && e1 >= 0:
+			aos, inPass = passCrossing(elevation, t0, t1), true
+		case inPass && e1 < 0:
+			passes = append(passes, passCulmination(elevation, gs.MinElevation, aos, passCrossing(elevation, t0, t1)))
+			inPass = false
+		case !inPass && e0 > ePrev && e0 > e1 && !tPrev.IsZero():
+			// sampled local maximum below the mask: a short pass may be hiding between the samples
+			tca := passMaximum(elevation, tPrev, t1)
+			if elevation(tca) >= 0 {
+				passes = append(passes, passCulmination(elevation, gs.MinElevation, passCrossing(elevation, tPrev, tca), passCrossing(elevation, tca, t1)))
+			}
+		}
+
+		tPrev, ePrev = t0, e0
+		t0, e0 = t1, e1
+	}
+	if propErr != nil {
+		return passes, propErr
+	}
+	if inPass {
+		passes = append(passes, passCulmination(elevation, gs.MinElevation, aos, stop))
+	}
+	return passes, nil
+}
+
+// Completes a pass from its AOS and LOS by searching the maximum elevation in between. elevation returns the
+// elevation above the mask, which is added back for MaxElevation.
+func passCulmination(elevation func(time.Time) float64, mask float64, aos, los time.Time) Pass {
+	tca := passMaximum(elevation, aos, los)
+	return Pass{AOS: aos, TCA: tca, LOS: los, MaxElevation: elevation(tca) + mask}
+}
+
+// Bisects the time at which the elevation crosses the mask between t0 and t1, whose elevations have opposite signs
+func passCrossing(elevation func(time.Time) float64, t0, t1 time.Time) time.Time {
+	rising := elevation(t0) < 0
+	for t1.Sub(t0) > passPrecision {
+		mid := t0.Add(t1.Sub(t0) / 2)
+		if (elevation(mid) < 0) == rising {
+			t0 = mid
+		} else {
+			t1 = mid
+		}
+	}
+	return t0.Add(t1.Sub(t0) / 2)
+}
+
+// Golden section search for the time of maximum elevation between t0 and t1, the elevation is assumed unimodal
+// over the interval (true for a single pass)
+func passMaximum(elevation func(time.Time) float64, t0, t1 time.Time) time.Time {
+	invPhi := (math.Sqrt(5) - 1) / 2
+	a, b := 0.0, t1.Sub(t0).Seconds()
+	at := func(s float64) time.Time { return t0.Add(time.Duration(s * 1e9)) }
+
+	c := b - invPhi*(b-a)
+	d := a + invPhi*(b-a)
+	ec, ed := elevation(at(c)), elevation(at(d))
+	for b-a > passPrecision.Seconds() {
+		if ec > ed {
+			b, d, ed = d, c, ec
+			c = b - invPhi*(b-a)
+			ec = elevation(at(c))
+		} else {
+			a, c, ec = c, d, ed
+			d = a + invPhi*(b-a)
+			ed = elevation(at(d))
+		}
+	}
+	return at((a + b) / 2)
+}
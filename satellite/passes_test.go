@@ -0,0 +1,66 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFindPasses(t *testing.T) {
+	sat := TLEToSat("1 00900U 64063C   22160.52204282  .00000408  00000+0  42495-3 0  9992",
+		"2 00900  90.1760  40.7701 0029467  47.9267  23.7177 13.73809888869573", GravityWGS84)
+	gs := GroundStation{Name: "Delft", Location: LatLongAlt{Latitude: 52.0 * DEG2RAD, Longitude: 4.37 * DEG2RAD, Altitude: 0.0}, MinElevation: 10 * DEG2RAD}
+	start := time.Date(2022, 6, 10, 0, 0, 0, 0, time.UTC)
+	stop := start.Add(24 * time.Hour)
+
+	passes, err := FindPasses(sat, gs, start, stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// brute force reference: count the rises in a 1 second scan
+	rises := 0
+	above := false
+	for ti := start; !ti.After(stop); ti = ti.Add(time.Second) {
+		look, _ := gs.LookAngles(sat, ti)
+		if look.El >= gs.MinElevation && !above {
+			rises++
+		}
+		above = look.El >= gs.MinElevation
+	}
+	if len(passes) != rises || rises == 0 {
+		t.Fatalf("Expected %d passes; but got %d", rises, len(passes))
+	}
+
+	for i, p := range passes {
+		if !(p.AOS.Before(p.TCA) && p.TCA.Before(p.LOS)) {
+			t.Errorf("Pass %d: expected AOS < TCA < LOS; but got %v %v %v", i, p.AOS, p.TCA, p.LOS)
+		}
+		if i > 0 && p.AOS.Before(passes[i-1].LOS) {
+			t.Errorf("Pass %d: overlaps the previous pass", i)
+		}
+		for _, edge := range []time.Time{p.AOS, p.LOS} {
+			look, _ := gs.LookAngles(sat, edge)
+			if math.Abs(look.El-gs.MinElevation) > 1e-5 {
+				t.Errorf("Pass %d: expected %f; but got %f", i, gs.MinElevation, look.El)
+			}
+		}
+		for _, dt := range []time.Duration{-time.Second, time.Second} {
+			look, _ := gs.LookAngles(sat, p.TCA.Add(dt))
+			if look.El > p.MaxElevation {
+				t.Errorf("Pass %d: elevation %f at TCA%+v exceeds maximum %f", i, look.El, dt, p.MaxElevation)
+			}
+		}
+	}
+
+	t.Run("Window clipping", func(t *testing.T) {
+		mid := passes[0].TCA
+		clipped, err := FindPasses(sat, gs, mid, passes[1].TCA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clipped) != 2 || !clipped[0].AOS.Equal(mid) || !clipped[1].LOS.Equal(passes[1].TCA) {
+			t.Errorf("Expected both passes clipped to the window; but got %+v", clipped)
+		}
+	})
+}
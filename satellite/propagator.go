@@ -0,0 +1,34 @@
+package satellite
+
+import "time"
+
+// Propagator computes the ECI (TEME for sgp4) position (km) and velocity (km/s) of a spacecraft at an instant
+type Propagator interface {
+	Propagate(t time.Time) (pos, vel Vector3, err error)
+	Epoch() time.Time
+}
+
+// SGP4Propagator propagates a TLE initialized Satellite with sgp4
+type SGP4Propagator struct {
+	Sat Satellite
+}
+
+// Wraps a Satellite initialized by TLEToSat (or TLEToSatWithOptions) as a Propagator
+func NewSGP4Propagator(sat Satellite) *SGP4Propagator {
+	return &SGP4Propagator{Sat: sat}
+}
+
+// Propagates the satellite to t, see PropagateAt
+func (p *SGP4Propagator) Propagate(t time.Time) (pos, vel Vector3, err error) {
+	return PropagateAt(p.Sat, t)
+}
+
+// Propagates the satellite to t, see PropagateTEME
+func (p *SGP4Propagator) PropagateTEME(t time.Time) (pos, vel TEMEVector, err error) {
+	return PropagateTEME(p.Sat, t)
+}
+
+// Returns the epoch of the element set
+func (p *SGP4Propagator) Epoch() time.Time {
+	return p.Sat.Epoch()
+}
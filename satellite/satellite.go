@@ -10,15 +10,20 @@ type Satellite struct {
 	Line1 string `json:"TLE_LINE1"`
 	Line2 string `json:"TLE_LINE2"`
 
-	satnum int64
+	satnum         int64
+	classification string
+	intldesg       string
+	elnum          int64
+	revnum         int64
 
 	Error      int64
 	ErrorStr   string
 	whichconst GravConst
 
-	epochyr    int64
-	epochdays  float64
-	jdsatepoch float64
+	epochyr     int64
+	epochdays   float64
+	jdsatepoch  float64 // whole day part of the epoch julian date
+	jdsatepochF float64 // fraction of day part of the epoch julian date
 
 	ndot  float64
 	nddot float64
@@ -32,6 +37,8 @@ type Satellite struct {
 	alta  float64
 	altp  float64
 
+	noKozai float64
+
 	method        string
 	operationmode string
 	init          string
@@ -1,7 +1,10 @@
 package satellite
 
 import (
+	"errors"
+	"math"
 	"testing"
+	"time"
 )
 
 func TestSimpleSatellite(t *testing.T) {
@@ -9,12 +12,40 @@ func TestSimpleSatellite(t *testing.T) {
 		ss := SimpleSatellite{Name: "Test 1",
 			Ole1: "1 00900U 64063C   22160.52204282  .00000408  00000+0  42495-3 0  9992",
 			Ole2: "2 00900  90.1760  40.7701 0029467  47.9267  23.7177 13.73809888869573"}
-		InitSat(&ss)
+		if err := InitSat(&ss, time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatal(err)
+		}
 
 		if ss.Lla.Latitude == 0 {
 			t.Errorf("Expected LLa.Lat non-zero; but got %f", ss.Lla.Latitude)
 		}
 	})
+	t.Run("Re-propagate Simple Satellite", func(t *testing.T) {
+		ss := SimpleSatellite{Name: "Test 1",
+			Ole1: "1 00900U 64063C   22160.52204282  .00000408  00000+0  42495-3 0  9992",
+			Ole2: "2 00900  90.1760  40.7701 0029467  47.9267  23.7177 13.73809888869573"}
+		t0 := time.Date(2022, 6, 10, 0, 0, 0, 0, time.UTC)
+		if err := InitSat(&ss, t0); err != nil {
+			t.Fatal(err)
+		}
+		first := ss.Position
+		t1 := t0.Add(90 * time.Second)
+		if err := ss.PropagateTo(t1); err != nil {
+			t.Fatal(err)
+		}
+		if !ss.Time.Equal(t1) {
+			t.Errorf("Expected %v; but got %v", t1, ss.Time)
+		}
+		if ss.Position == first {
+			t.Errorf("Expected position to change after re-propagation")
+		}
+		// the longitude must be derived from the sidereal time of the propagation instant
+		jd, jdFrac := JDayTime(t1)
+		lon := math.Atan2(ss.Position.Y, ss.Position.X) - gstime(jd+jdFrac)
+		if math.Abs(math.Remainder(ss.Lla.Longitude-lon, TWOPI)) > 1e-9 {
+			t.Errorf("Expected %f; but got %f", lon, ss.Lla.Longitude)
+		}
+	})
 }
 
 func TestParseTLE(t *testing.T) {
@@ -102,3 +133,117 @@ func TestParseTLE(t *testing.T) {
 
 	})
 }
+
+func TestPropagateAt(t *testing.T) {
+	sat := TLEToSat("1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753", "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667", GravityWGS72)
+
+	t.Run("Reference Time", func(t *testing.T) {
+		// 360 minutes after epoch, from the Vallado verification output
+		pos, _, err := PropagateAt(sat, time.Date(2000, 6, 28, 0, 50, 19, 733571000, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := Vector3{X: -7154.03120202, Y: -3783.17682504, Z: -3536.19412294}
+		if math.Abs(pos.X-expected.X) > 1e-3 || math.Abs(pos.Y-expected.Y) > 1e-3 || math.Abs(pos.Z-expected.Z) > 1e-3 {
+			t.Errorf("Expected %v; but got %v", expected, pos)
+		}
+	})
+	t.Run("Millisecond Steps", func(t *testing.T) {
+		t0 := time.Date(2000, 6, 28, 0, 50, 19, 0, time.UTC)
+		p0, v0, _ := PropagateAt(sat, t0)
+		p1, _, _ := PropagateAt(sat, t0.Add(time.Millisecond))
+		// the satellite should move about |v| * 1ms
+		moved := math.Sqrt((p1.X-p0.X)*(p1.X-p0.X) + (p1.Y-p0.Y)*(p1.Y-p0.Y) + (p1.Z-p0.Z)*(p1.Z-p0.Z))
+		speed := math.Sqrt(v0.X*v0.X + v0.Y*v0.Y + v0.Z*v0.Z)
+		if math.Abs(moved-speed*1e-3) > 1e-6 {
+			t.Errorf("Expected %f; but got %f", speed*1e-3, moved)
+		}
+	})
+	t.Run("Minutes Since Epoch", func(t *testing.T) {
+		pos, _, err := PropagateMinutes(sat, 720.0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := Vector3{X: -7134.59340119, Y: 6531.68641334, Z: 3260.27186483}
+		if math.Abs(pos.X-expected.X) > 1e-4 || math.Abs(pos.Y-expected.Y) > 1e-4 || math.Abs(pos.Z-expected.Z) > 1e-4 {
+			t.Errorf("Expected %v; but got %v", expected, pos)
+		}
+	})
+	t.Run("TEME", func(t *testing.T) {
+		at := time.Date(2000, 6, 28, 0, 50, 19, 733571000, time.UTC)
+		pos, vel, _ := PropagateAt(sat, at)
+		teme, temeVel, err := PropagateTEME(sat, at)
+		if err != nil || Vector3(teme) != pos || Vector3(temeVel) != vel {
+			t.Errorf("Expected %v; but got %v (%v)", pos, teme, err)
+		}
+		minutes, _, _ := PropagateMinutesTEME(sat, 720.0)
+		expected, _, _ := PropagateMinutes(sat, 720.0)
+		if Vector3(minutes) != expected {
+			t.Errorf("Expected %v; but got %v", expected, minutes)
+		}
+	})
+}
+
+func TestPropagationErrors(t *testing.T) {
+	// synthetic low orbit with an extreme drag term, decays within a day
+	sat := TLEToSat("1 99999U          22100.50000000  .00000000  00000+0  50000-1 0    03", "2 99999  51.6000   0.0000 0005000   0.0000   0.0000 16.00000000    01", GravityWGS72)
+
+	t.Run("No Error At Epoch", func(t *testing.T) {
+		if _, _, err := PropagateMinutes(sat, 0); err != nil {
+			t.Errorf("Expected no error; but got %v", err)
+		}
+	})
+	t.Run("Decayed", func(t *testing.T) {
+		_, _, err := PropagateMinutes(sat, 1440)
+		if !errors.Is(err, ErrDecayed) {
+			t.Errorf("Expected %v; but got %v", ErrDecayed, err)
+		}
+		var perr *PropagationError
+		if !errors.As(err, &perr) || perr.Code != 6 || perr.SatNum != 99999 {
+			t.Errorf("Expected sgp4 error 6 for 99999; but got %v", err)
+		}
+	})
+	t.Run("Mean Eccentricity", func(t *testing.T) {
+		_, _, err := PropagateMinutes(sat, 5000)
+		if !errors.Is(err, ErrMeanEccentricity) {
+			t.Errorf("Expected %v; but got %v", ErrMeanEccentricity, err)
+		}
+	})
+}
+
+func TestSGP4OptionsErrors(t *testing.T) {
+	line1 := "1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753"
+	line2 := "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667"
+	cases := []struct {
+		name     string
+		opts     SGP4Options
+		expected error
+	}{
+		{"Operation mode", SGP4Options{OpsMode: "x"}, ErrOpsMode},
+		{"Gravity", SGP4Options{Gravity: "wgs60"}, ErrGravity},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := TLEToSatWithOptions(line1, line2, c.opts); !errors.Is(err, c.expected) {
+				t.Errorf("Expected %v; but got %v", c.expected, err)
+			}
+		})
+	}
+	t.Run("Valid options", func(t *testing.T) {
+		if _, err := TLEToSatWithOptions(line1, line2, SGP4Options{OpsMode: OpsModeAFSPC}); err != nil {
+			t.Errorf("Expected no error; but got %v", err)
+		}
+	})
+	t.Run("sgp4init", func(t *testing.T) {
+		// perigee below the surface, sgp4init fails at epoch
+		sat, err := TLEToSatWithOptions("1 99998U          22100.50000000  .00000000  00000+0  10000-2 0    09",
+			"2 99998  51.6000   0.0000 9500000   0.0000   0.0000  2.00000000    04", SGP4Options{})
+		var perr *PropagationError
+		if !errors.As(err, &perr) || perr.Code != 6 || perr.Tsince != 0 || !errors.Is(err, ErrDecayed) {
+			t.Errorf("Expected sgp4 error 6 at epoch; but got %v", err)
+		}
+		if sat.satnum != 99998 {
+			t.Errorf("Expected %v; but got %v", 99998, sat.satnum)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package satellite
 
 import (
 	"math"
+	"time"
 )
 
 // this procedure initializes variables for sgp4.
@@ -12,7 +13,7 @@ func sgp4init(opsmode *string, epoch float64, satrec *Satellite) (position, velo
 	var cosim, sinim, em, emsq, argpm, nodem, inclm, mm, nm, s1, s2, s3, s4, s5, ss1, ss2, ss3, ss4, ss5, sz1, sz3, sz11, sz13, sz21, sz23, sz31, sz33, tc, z1, z3, z11, z13, z21, z23, z31, z33, xpidot float64
 
 	satrec.method = "n"
-	satrec.operationmode = "i"
+	satrec.operationmode = *opsmode
 
 	radiusearthkm := satrec.whichconst.radiusearthkm
 	j2 := satrec.whichconst.j2
@@ -33,6 +34,10 @@ func sgp4init(opsmode *string, epoch float64, satrec *Satellite) (position, velo
 	satrec.con41 = con41
 	satrec.gsto = gsto
 
+	// apogee and perigee heights in earth radii
+	satrec.alta = ao*(1.0+satrec.ecco) - 1.0
+	satrec.altp = ao*(1.0-satrec.ecco) - 1.0
+
 	satrec.Error = 0
 
 	if omeosq >= 0.0 || satrec.no >= 0.0 {
@@ -284,11 +289,41 @@ func initl(satn int64, grav GravConst, ecco, epoch, inclo, noIn float64, methodI
 	return
 }
 
-// Calculates position and velocity vectors for given time
-func Propagate(sat Satellite, year int, month int, day, hours, minutes, seconds int) (position, velocity Vector3) {
-	j := JDay(year, month, day, hours, minutes, seconds)
-	m := (j - sat.jdsatepoch) * 1440
-	return sgp4(&sat, m)
+// Calculates position and velocity vectors for given time.
+// A *PropagationError is returned when sgp4 flags the result as invalid, e.g. for a decayed orbit.
+func Propagate(sat Satellite, year int, month int, day, hours, minutes, seconds int) (position, velocity Vector3, err error) {
+	jd, jdFrac := JDayFrac(year, month, day, hours, minutes, float64(seconds))
+	return PropagateMinutes(sat, minutesSinceEpoch(sat, jd, jdFrac))
+}
+
+// Calculates position and velocity vectors for given time, keeping sub-second precision
+func PropagateAt(sat Satellite, t time.Time) (position, velocity Vector3, err error) {
+	jd, jdFrac := JDayTime(t)
+	return PropagateMinutes(sat, minutesSinceEpoch(sat, jd, jdFrac))
+}
+
+// Calculates position and velocity vectors tsince minutes after the satellite epoch
+func PropagateMinutes(sat Satellite, tsince float64) (position, velocity Vector3, err error) {
+	position, velocity = sgp4(&sat, tsince)
+	err = sgp4Error(&sat)
+	return
+}
+
+// Like PropagateAt, with the result typed as TEME vectors for the frame conversions of frames.go
+func PropagateTEME(sat Satellite, t time.Time) (position, velocity TEMEVector, err error) {
+	pos, vel, err := PropagateAt(sat, t)
+	return TEMEVector(pos), TEMEVector(vel), err
+}
+
+// Like PropagateMinutes, with the result typed as TEME vectors
+func PropagateMinutesTEME(sat Satellite, tsince float64) (position, velocity TEMEVector, err error) {
+	pos, vel, err := PropagateMinutes(sat, tsince)
+	return TEMEVector(pos), TEMEVector(vel), err
+}
+
+// Minutes from the satellite epoch to a split julian date, differencing the whole and fractional parts separately
+func minutesSinceEpoch(sat Satellite, jd, jdFrac float64) float64 {
+	return ((jd - sat.jdsatepoch) + (jdFrac - sat.jdsatepochF)) * 1440.0
 }
 
 // this procedure is the sgp4 prediction model from space command. this is an updated and combined version of sgp4 and sdp4, which were originally published separately in spacetrack report #3. this version follows the methodology from the aiaa paper (2006) describing the history and development of the code.
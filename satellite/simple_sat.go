@@ -1,15 +1,29 @@
 package satellite
 
+import "time"
+
 // satellite data generated from https://www.celestrak.com/NORAD/elements/table.php?GROUP=active&FORMAT=tle
 // TODO: need to check copyright or PR
 
 //type SimpleSatellite struct
 //SimpleSatellite is intended to abstract away all of the Satellite orbital calculations. Contains LLA
+//Position, Velocity and Lla all refer to the same instant, Time.
 type SimpleSatellite struct {
-	Name string
-	Ole1 string
-	Ole2 string
-	Lla  LatLongAlt
+	Name     string
+	Ole1     string
+	Ole2     string
+	Lla      LatLongAlt
+	Position Vector3   // ECI position in km
+	Velocity Vector3   // ECI velocity in km/s
+	Time     time.Time // instant the position, velocity and LLA were computed for
+	sat      *Satellite
+	prop     Propagator
+}
+
+//NewSimpleSatellite creates a satellite without a TLE, e.g. a synthetic or maneuvering spacecraft driven by a
+//NumericalPropagator. Call PropagateTo to fill in the state.
+func NewSimpleSatellite(name string, prop Propagator) SimpleSatellite {
+	return SimpleSatellite{Name: name, prop: prop}
 }
 
 //lint:ignore U1000 Ignore unused function
@@ -17,9 +31,32 @@ func (s *SimpleSatellite) ToString() [3]string {
 	return [3]string{s.Name, s.Ole1, s.Ole2}
 }
 
-//func initSat pulls the TLE data to generate a LLA position and sets the LLA variable.
-func InitSat(s *SimpleSatellite) {
-	temp_sat := TLEToSat(s.Ole1, s.Ole2, GravityWGS84)
-	pos, _ := Propagate(temp_sat, 2022, 6, 1, 0, 0, 0)
-	s.Lla = ECIToLLA(pos, GSTimeFromDate(2022, 1, 1, 0, 0, 0))
+//func initSat pulls the TLE data and propagates it to t, setting the position, velocity and LLA variables.
+//Returns the propagator's error (a *PropagationError for sgp4) if the satellite cannot be propagated, in which
+//case the state is left unchanged.
+//Satellites created by NewSimpleSatellite keep their propagator.
+func InitSat(s *SimpleSatellite, t time.Time) error {
+	if s.prop == nil || s.Ole1 != "" {
+		temp_sat := TLEToSat(s.Ole1, s.Ole2, GravityWGS84)
+		s.sat = &temp_sat
+		s.prop = NewSGP4Propagator(temp_sat)
+	}
+	return s.PropagateTo(t)
+}
+
+//PropagateTo re-propagates an initialized satellite to t. The TLE is parsed first if InitSat was never called.
+func (s *SimpleSatellite) PropagateTo(t time.Time) error {
+	if s.prop == nil {
+		return InitSat(s, t)
+	}
+	pos, vel, err := s.prop.Propagate(t)
+	if err != nil {
+		return err
+	}
+	jd, jdFrac := JDayTime(t)
+	s.Position = pos
+	s.Velocity = vel
+	s.Time = t
+	s.Lla = ECIToLLA(pos, GMST(jd+jdFrac))
+	return nil
 }
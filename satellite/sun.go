@@ -0,0 +1,217 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Astronomical unit (km)
+const astronomicalUnit float64 = 149597870.7
+
+// Equatorial radius of the Sun (km)
+const sunRadius float64 = 696000.0
+
+// Step of the coarse shadow scan in FindEclipses, the penumbra of a LEO satellite lasts only seconds but a
+// skipped penumbra is still recovered by the bisection
+const eclipseScanStep = 30 * time.Second
+
+// Lighting condition of a satellite in the conical shadow model
+type Illumination int
+
+const (
+	Sunlit Illumination = iota
+	Penumbra
+	Umbra
+)
+
+func (i Illumination) String() string {
+	switch i {
+	case Sunlit:
+		return "sunlit"
+	case Penumbra:
+		return "penumbra"
+	case Umbra:
+		return "umbra"
+	}
+	return "unknown"
+}
+
+// A change of the lighting condition of a satellite
+type EclipseEvent struct {
+	Time     time.Time
+	From, To Illumination
+}
+
+// Calculates the geocentric position (km) of the Sun in the mean equator and equinox of date frame, which is
+// within arcseconds of TEME and ECI for shadow purposes. jdtt is the TT julian date (UT1 or UTC do as well).
+// The low precision ephemeris is good to 0.01 deg between 1950 and 2050.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, algorithm 29
+func SunPosition(jdtt float64) (pos Vector3) {
+	ttt := (jdtt - 2451545.0) / 36525.0
+	meanLong := math.Mod(280.460+36000.771*ttt, 360.0)
+	meanAnomaly := math.Mod(357.5291092+35999.05034*ttt, 360.0) * DEG2RAD
+	eclLong := (meanLong + 1.914666471*math.Sin(meanAnomaly) + 0.019994643*math.Sin(2.0*meanAnomaly)) * DEG2RAD
+	obliquity := (23.439291 - 0.0130042*ttt) * DEG2RAD
+	magr := (1.000140612 - 0.016708617*math.Cos(meanAnomaly) - 0.000139589*math.Cos(2.0*meanAnomaly)) * astronomicalUnit
+
+	sinLong, cosLong := math.Sincos(eclLong)
+	pos.X = magr * cosLong
+	pos.Y = magr * math.Cos(obliquity) * sinLong
+	pos.Z = magr * math.Sin(obliquity) * sinLong
+	return
+}
+
+// Determines the lighting condition of a satellite at satPos (km) with the Sun at sunPos (km), both geocentric
+// in the same frame, using a conical Earth shadow of the WGS84 equatorial radius.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, algorithm 34
+func ShadowState(satPos, sunPos Vector3) Illumination {
+	return illumination(shadowMargins(satPos, sunPos))
+}
+
+// Returns the distances (km) of the satellite outside the penumbra and umbra cones, perpendicular to the shadow
+// axis and negative inside. On the sunward side of the Earth the margins at the terminator plane are continued,
+// so both are continuous and positive there.
+func shadowMargins(satPos, sunPos Vector3) (penumbra, umbra float64) {
+	sunDist := math.Sqrt(sunPos.X*sunPos.X + sunPos.Y*sunPos.Y + sunPos.Z*sunPos.Z)
+	satDist := math.Sqrt(satPos.X*satPos.X + satPos.Y*satPos.Y + satPos.Z*satPos.Z)
+
+	// distance of the satellite behind the Earth along the anti-sun axis, and from that axis
+	behind := -(satPos.X*sunPos.X + satPos.Y*sunPos.Y + satPos.Z*sunPos.Z) / sunDist
+	off := math.Sqrt(math.Max(satDist*satDist-behind*behind, 0))
+	if behind <= 0 {
+		behind, off = 0, satDist
+	}
+
+	// half angles of the umbra and penumbra cones
+	alphaUmb := math.Asin((sunRadius - wgs84A) / sunDist)
+	alphaPen := math.Asin((sunRadius + wgs84A) / sunDist)
+
+	penumbra = off - math.Tan(alphaPen)*(wgs84A/math.Sin(alphaPen)+behind)
+	umbra = off - math.Tan(alphaUmb)*(wgs84A/math.Sin(alphaUmb)-behind)
+	return
+}
+
+// Returns the lighting condition for the margins of shadowMargins
+func illumination(penumbra, umbra float64) Illumination {
+	if penumbra > 0 {
+		return Sunlit
+	}
+	if umbra > 0 {
+		return Penumbra
+	}
+	return Umbra
+}
+
+// Determines the lighting condition of the satellite at t
+func Eclipse(sat Satellite, t time.Time) (Illumination, error) {
+	pos, _, err := PropagateTEME(sat, t)
+	if err != nil {
+		return Sunlit, err
+	}
+	// the mean of date sun position is within arcseconds of TEME
+	return ShadowState(Vector3(pos), SunPosition(NewTimeScales(t, nil).TT.JD())), nil
+}
+
+// Finds the shadow entries and exits of the satellite between start and stop, in chronological order.
+// The shadow is scanned in steps of eclipseScanStep and every change is bisected to a millisecond. A step that
+// spans more than one change, e.g. from sunlit to umbra, is refined change by change, so the penumbra entry and
+// exit around every umbra are reported. Passages shorter than a step, such as grazing passes at the shadow edge,
+// leave the same state at both ends of the step: they are found by a golden section search for the minimum of
+// the distance to the next deeper shadow cone around every sample where that distance has a local minimum.
+// On a propagation error the events found so far are returned with the error.
+func FindEclipses(sat Satellite, start, stop time.Time) ([]EclipseEvent, error) {
+	if stop.Before(start) {
+		return nil, errors.New("eclipse window stop is before start")
+	}
+
+	var propErr error
+	margins := func(t time.Time) (penumbra, umbra float64) {
+		if propErr != nil {
+			return math.Inf(1), math.Inf(1)
+		}
+		pos, _, err := PropagateTEME(sat, t)
+		if err != nil {
+			propErr = err
+			return math.Inf(1), math.Inf(1)
+		}
+		// the mean of date sun position is within arcseconds of TEME
+		return shadowMargins(Vector3(pos), SunPosition(NewTimeScales(t, nil).TT.JD()))
+	}
+	state := func(t time.Time) Illumination {
+		return illumination(margins(t))
+	}
+	// distance to the cone of the next deeper shadow than s, infinite in the umbra
+	depth := func(t time.Time, s Illumination) float64 {
+		penumbra, umbra := margins(t)
+		switch s {
+		case Sunlit:
+			return penumbra
+		case Penumbra:
+			return umbra
+		}
+		return math.Inf(1)
+	}
+
+	var events []EclipseEvent
+	// appends the changes between t0 and t1, a step may span more than one (sunlit -> penumbra -> umbra)
+	refine := func(t0 time.Time, s0 Illumination, t1 time.Time, s1 Illumination) {
+		for s0 != s1 && propErr == nil {
+			lo, hi := t0, t1
+			for hi.Sub(lo) > time.Millisecond {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if state(mid) == s0 {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			next := state(hi)
+			events = append(events, EclipseEvent{Time: hi, From: s0, To: next})
+			t0, s0 = hi, next
+		}
+	}
+	// looks for a passage into a deeper shadow between t0 and t1, which are both in s
+	dip := func(t0, t1 time.Time, s Illumination) {
+		tm := passMaximum(func(t time.Time) float64 { return -depth(t, s) }, t0, t1)
+		sm := state(tm)
+		if s == Sunlit && sm == Penumbra {
+			// a short umbra lies around the minimum of its own cone distance
+			if tu := passMaximum(func(t time.Time) float64 { return -depth(t, Penumbra) }, t0, t1); state(tu) == Umbra {
+				tm, sm = tu, Umbra
+			}
+		}
+		if sm > s && propErr == nil {
+			refine(t0, s, tm, sm)
+			refine(tm, sm, t1, s)
+		}
+	}
+
+	t0 := start
+	s0, d0 := state(t0), depth(t0, state(t0))
+	prev, prevState, prevDepth := t0, s0, math.Inf(1) // the window start counts as a local minimum
+	for t0.Before(stop) && propErr == nil {
+		t1 := t0.Add(eclipseScanStep)
+		if t1.After(stop) {
+			t1 = stop
+		}
+		s1 := state(t1)
+		d1 := depth(t1, s1)
+
+		switch {
+		case s0 != s1:
+			refine(t0, s0, t1, s1)
+		case prevState == s0 && d0 <= prevDepth && d0 <= d1:
+			dip(prev, t1, s0)
+		case t1.Equal(stop) && d1 < d0:
+			// still approaching the deeper shadow at the window stop
+			dip(t0, t1, s0)
+		}
+		prev, prevState, prevDepth = t0, s0, d0
+		t0, s0, d0 = t1, s1, d1
+	}
+	if propErr != nil {
+		return events, propErr
+	}
+	return events, nil
+}
@@ -0,0 +1,134 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSunPosition(t *testing.T) {
+	// Vallado, Fundamentals of Astrodynamics and Applications, example 5-1
+	sun := SunPosition(2453827.5)
+	expected := Vector3{X: 0.9771945, Y: 0.1924424, Z: 0.0834308}
+	got := Vector3{X: sun.X / astronomicalUnit, Y: sun.Y / astronomicalUnit, Z: sun.Z / astronomicalUnit}
+	if math.Abs(got.X-expected.X) > 1e-5 || math.Abs(got.Y-expected.Y) > 1e-5 || math.Abs(got.Z-expected.Z) > 1e-5 {
+		t.Errorf("Expected %v; but got %v", expected, got)
+	}
+}
+
+func TestShadowState(t *testing.T) {
+	sun := Vector3{X: astronomicalUnit}
+	cases := []struct {
+		name     string
+		pos      Vector3
+		expected Illumination
+	}{
+		{"Day side", Vector3{X: 7000}, Sunlit},
+		{"Terminator", Vector3{Y: 7000}, Sunlit},
+		{"Behind", Vector3{X: -7000}, Umbra},
+		{"Umbra edge", Vector3{X: -7000, Z: 6300}, Umbra},
+		{"Penumbra", Vector3{X: -7000, Z: 6380}, Penumbra},
+		{"Outside", Vector3{X: -7000, Z: 6450}, Sunlit},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ShadowState(c.pos, sun); got != c.expected {
+				t.Errorf("Expected %v; but got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestFindEclipses(t *testing.T) {
+	sat := TLEToSat("1 00900U 64063C   22160.52204282  .00000408  00000+0  42495-3 0  9992",
+		"2 00900  90.1760  40.7701 0029467  47.9267  23.7177 13.73809888869573", GravityWGS84)
+	start := time.Date(2022, 6, 10, 0, 0, 0, 0, time.UTC)
+	events, err := FindEclipses(sat, start, start.Add(6*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) < 8 {
+		t.Fatalf("Expected several eclipses; but got %d events", len(events))
+	}
+
+	cycle := []Illumination{Penumbra, Umbra, Penumbra, Sunlit}
+	offset := -1
+	for i, c := range cycle {
+		if events[0].To == c && events[0].From == cycle[(i+len(cycle)-1)%len(cycle)] {
+			offset = i
+		}
+	}
+	for i, e := range events {
+		if i > 0 && e.From != events[i-1].To {
+			t.Errorf("Event %d: expected %v; but got %v", i, events[i-1].To, e.From)
+		}
+		if expected := cycle[(i+offset)%len(cycle)]; e.To != expected {
+			t.Errorf("Event %d: expected %v; but got %v", i, expected, e.To)
+		}
+		before, _ := Eclipse(sat, e.Time.Add(-2*time.Millisecond))
+		after, _ := Eclipse(sat, e.Time)
+		if before != e.From || after != e.To {
+			t.Errorf("Event %d: expected %v -> %v; but got %v -> %v", i, e.From, e.To, before, after)
+		}
+	}
+
+	// a single step from sunlit to umbra is split at the penumbra entry
+	for i, e := range events[:len(events)-1] {
+		next := events[i+1]
+		if e.From != Sunlit || next.To != Umbra || next.Time.Sub(e.Time) >= eclipseScanStep-time.Second {
+			continue
+		}
+		split, err := FindEclipses(sat, e.Time.Add(-time.Second), e.Time.Add(eclipseScanStep-time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(split) != 2 || split[0].To != Penumbra || split[1].To != Umbra {
+			t.Fatalf("Expected sunlit -> penumbra -> umbra; but got %v", split)
+		}
+		for k, ev := range []EclipseEvent{e, next} {
+			if d := split[k].Time.Sub(ev.Time); d < -time.Millisecond || d > time.Millisecond {
+				t.Errorf("Expected %v; but got %v", ev.Time, split[k].Time)
+			}
+		}
+		return
+	}
+	t.Error("Expected a penumbra shorter than a scan step")
+}
+
+func TestFindEclipsesShort(t *testing.T) {
+	// polar orbit whose plane is tilted just into the shadow cone: passages of a few seconds to half a minute
+	sat := TLEToSat("1 90001U          22161.00000000  .00000000  00000+0  00000+0 0  9990",
+		"2 90001  92.5960 169.0000 0000000   0.0000   0.0000 14.57000000    17", GravityWGS84)
+	start := time.Date(2022, 6, 10, 0, 0, 0, 0, time.UTC)
+	stop := start.Add(6 * time.Hour)
+
+	// changes of a scan in one second steps
+	var expected []EclipseEvent
+	prev, _ := Eclipse(sat, start)
+	for ts := start.Add(time.Second); !ts.After(stop); ts = ts.Add(time.Second) {
+		s, _ := Eclipse(sat, ts)
+		if s != prev {
+			expected = append(expected, EclipseEvent{Time: ts, From: prev, To: s})
+			prev = s
+		}
+	}
+	if len(expected) < 4 {
+		t.Fatalf("Expected short passages; but got %d events", len(expected))
+	}
+
+	// the passages must be found whatever the phase of the scan
+	for offset := time.Duration(0); offset < eclipseScanStep; offset += 3 * time.Second {
+		events, err := FindEclipses(sat, start.Add(-offset), stop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != len(expected) {
+			t.Fatalf("Offset %v: expected %d events; but got %d", offset, len(expected), len(events))
+		}
+		for i, e := range events {
+			if e.From != expected[i].From || e.To != expected[i].To || expected[i].Time.Sub(e.Time) < 0 || expected[i].Time.Sub(e.Time) > time.Second {
+				t.Errorf("Offset %v: expected %v; but got %v", offset, expected[i], e)
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package satellite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TT - TAI in seconds
+const ttMinusTAI float64 = 32.184
+
+// MJD of the unix epoch, 1970-01-01 00:00 UTC
+const mjdUnixEpoch float64 = 40587.0
+
+// TAI - UTC, in effect from the given UTC date onwards
+// Reference: IERS Bulletin C
+var leapSeconds = [...]struct {
+	year  int
+	month time.Month
+	delta float64
+}{
+	{1972, time.January, 10},
+	{1972, time.July, 11},
+	{1973, time.January, 12},
+	{1974, time.January, 13},
+	{1975, time.January, 14},
+	{1976, time.January, 15},
+	{1977, time.January, 16},
+	{1978, time.January, 17},
+	{1979, time.January, 18},
+	{1980, time.January, 19},
+	{1981, time.July, 20},
+	{1982, time.July, 21},
+	{1983, time.July, 22},
+	{1985, time.July, 23},
+	{1988, time.January, 24},
+	{1990, time.January, 25},
+	{1991, time.January, 26},
+	{1992, time.July, 27},
+	{1993, time.July, 28},
+	{1994, time.July, 29},
+	{1996, time.January, 30},
+	{1997, time.July, 31},
+	{1999, time.January, 32},
+	{2006, time.January, 33},
+	{2009, time.January, 34},
+	{2012, time.July, 35},
+	{2015, time.July, 36},
+	{2017, time.January, 37},
+}
+
+// Returns TAI - UTC in seconds for a UTC instant. Before 1972 UTC was not an integer offset from TAI; the
+// 1972 value is returned for those dates.
+func TAIMinusUTC(t time.Time) float64 {
+	t = t.UTC()
+	delta := leapSeconds[0].delta
+	for _, ls := range leapSeconds {
+		if t.Before(time.Date(ls.year, ls.month, 1, 0, 0, 0, 0, time.UTC)) {
+			break
+		}
+		delta = ls.delta
+	}
+	return delta
+}
+
+// Holds the Earth orientation parameters of one day
+type EOPRecord struct {
+	MJD         float64 // modified julian date (UTC) at 0h
+	XP, YP      float64 // pole coordinates (arcsec)
+	UT1MinusUTC float64 // UT1 - UTC (s)
+	LOD         float64 // excess length of day (ms)
+}
+
+// Holds daily Earth orientation parameters, sorted by date. A nil *EOPTable is valid and yields zero values.
+type EOPTable struct {
+	records []EOPRecord
+}
+
+// Reads an IERS finals file (finals.all, finals.data, finals2000A.* in the fixed column "finals" format)
+func LoadEOP(path string) (*EOPTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadEOP(f)
+}
+
+// Reads Earth orientation parameters in the IERS finals format. Days without polar motion or UT1-UTC values
+// (the far end of the prediction span) are skipped.
+func ReadEOP(r io.Reader) (*EOPTable, error) {
+	table := &EOPTable{}
+	lineNr := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNr++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := []string{tleField(line, 7, 15), tleField(line, 18, 27), tleField(line, 37, 46), tleField(line, 58, 68), tleField(line, 79, 86)}
+		if strings.TrimSpace(fields[1]) == "" || strings.TrimSpace(fields[3]) == "" {
+			continue
+		}
+		var values [5]float64
+		for i, field := range fields {
+			field = strings.TrimSpace(field)
+			if field == "" { // LOD is not always filled
+				continue
+			}
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNr, err)
+			}
+			values[i] = v
+		}
+		table.records = append(table.records, EOPRecord{MJD: values[0], XP: values[1], YP: values[2], UT1MinusUTC: values[3], LOD: values[4]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(table.records, func(a, b int) bool { return table.records[a].MJD < table.records[b].MJD })
+	return table, nil
+}
+
+// Returns the Earth orientation parameters at a UTC instant, linearly interpolated between the daily values.
+// UT1 - UTC is interpolated as UT1 - TAI so that leap seconds do not smear into the neighbouring day.
+// The second result is false (and the record zero) if the instant is outside the table.
+func (e *EOPTable) At(t time.Time) (EOPRecord, bool) {
+	if e == nil || len(e.records) == 0 {
+		return EOPRecord{}, false
+	}
+	mjd := mjdUnixEpoch + float64(t.UnixNano())/86400e9
+	i := sort.Search(len(e.records), func(i int) bool { return e.records[i].MJD > mjd })
+	if i == 0 || (i == len(e.records) && mjd > e.records[i-1].MJD) {
+		return EOPRecord{}, false
+	}
+	if i == len(e.records) {
+		return e.records[i-1], true
+	}
+
+	a, b := e.records[i-1], e.records[i]
+	frac := (mjd - a.MJD) / (b.MJD - a.MJD)
+	lerp := func(x, y float64) float64 { return x + (y-x)*frac }
+
+	taiA := TAIMinusUTC(mjdToTime(a.MJD))
+	taiB := TAIMinusUTC(mjdToTime(b.MJD))
+	ut1tai := lerp(a.UT1MinusUTC-taiA, b.UT1MinusUTC-taiB)
+
+	return EOPRecord{
+		MJD:         mjd,
+		XP:          lerp(a.XP, b.XP),
+		YP:          lerp(a.YP, b.YP),
+		UT1MinusUTC: ut1tai + TAIMinusUTC(t),
+		LOD:         lerp(a.LOD, b.LOD),
+	}, true
+}
+
+// A julian date split into a whole day part and a fraction of a day, see JDayFrac
+type JulianDate struct {
+	Day, Frac float64
+}
+
+// Returns the julian date as a single float64
+func (j JulianDate) JD() float64 {
+	return j.Day + j.Frac
+}
+
+// Holds one instant expressed in the UTC, UT1, TAI and TT time scales, together with the polar motion at that instant
+type TimeScales struct {
+	UTC, UT1, TAI, TT JulianDate
+	XP, YP            float64 // pole coordinates (radians), zero without EOP data
+}
+
+// Expresses a UTC instant in all time scales. eop may be nil, in which case UT1 = UTC and polar motion is zero.
+func NewTimeScales(t time.Time, eop *EOPTable) (ts TimeScales) {
+	t = t.UTC()
+	orientation, _ := eop.At(t)
+	tai := secondsDuration(TAIMinusUTC(t))
+
+	ts.UTC = julianDate(t)
+	ts.UT1 = julianDate(t.Add(secondsDuration(orientation.UT1MinusUTC)))
+	ts.TAI = julianDate(t.Add(tai))
+	ts.TT = julianDate(t.Add(tai + secondsDuration(ttMinusTAI)))
+	ts.XP = orientation.XP * DEG2RAD / 3600.0
+	ts.YP = orientation.YP * DEG2RAD / 3600.0
+	return
+}
+
+// Greenwich mean sidereal time (IAU-82) of the instant, radians
+func (ts TimeScales) GMST() float64 {
+	return GMST(ts.UT1.JD())
+}
+
+// Earth rotation angle (IAU 2000) of the instant, radians
+func (ts TimeScales) ERA() float64 {
+	return ERA(ts.UT1.JD())
+}
+
+// Converts TEME coordinates into ITRF at the instant, using UT1 and polar motion, see TEMEToITRF
+func TEMEToITRFAt(r, v TEMEVector, ts TimeScales) (ITRFVector, ITRFVector) {
+	return TEMEToITRF(r, v, ts.UT1.JD(), ts.XP, ts.YP)
+}
+
+// Converts TEME coordinates into GCRF at the instant, see TEMEToGCRF
+func TEMEToGCRFAt(r, v TEMEVector, ts TimeScales) (GCRFVector, GCRFVector) {
+	return TEMEToGCRF(r, v, ts.TT.JD())
+}
+
+// Greenwich mean sidereal time (IAU-82) in radians for a UT1 julian date. This is the sidereal time used by all
+// conversions in the package.
+func GMST(jdut1 float64) float64 {
+	return gstime(jdut1)
+}
+
+// Earth rotation angle (IAU 2000) in radians for a UT1 julian date
+func ERA(jdut1 float64) float64 {
+	du := jdut1 - 2451545.0
+	// the whole days of du are whole turns, only the fraction of a day enters the angle
+	_, frac := math.Modf(du)
+	era := TWOPI * math.Mod(0.7790572732640+frac+0.00273781191135448*du, 1.0)
+	if era < 0 {
+		era += TWOPI
+	}
+	return era
+}
+
+func julianDate(t time.Time) JulianDate {
+	jd, jdFrac := JDayTime(t)
+	return JulianDate{Day: jd, Frac: jdFrac}
+}
+
+func mjdToTime(mjd float64) time.Time {
+	return time.Unix(0, 0).UTC().Add(time.Duration(math.Round((mjd - mjdUnixEpoch) * 86400e9)))
+}
+
+func secondsDuration(s float64) time.Duration {
+	return time.Duration(math.Round(s * 1e9))
+}
@@ -0,0 +1,137 @@
+package satellite
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Two days in the IERS finals format around Vallado example 3-15, the second day is made up
+const finalsSample = `04 4 6 53101.00 I -0.140682 0.000100  0.333309 0.000100  I-0.4399619 0.0000100  1.5563 0.0100
+04 4 7 53102.00 I -0.140292 0.000100  0.332836 0.000100  I-0.4409619 0.0000100  1.4883 0.0100
+04 4 8 53103.00 P -0.139900 0.000100  0.332300 0.000100  P
+`
+
+func TestTAIMinusUTC(t *testing.T) {
+	cases := []struct {
+		t        time.Time
+		expected float64
+	}{
+		{time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), 10},
+		{time.Date(1972, 6, 30, 23, 59, 59, 0, time.UTC), 10},
+		{time.Date(1972, 7, 1, 0, 0, 0, 0, time.UTC), 11},
+		{time.Date(2004, 4, 6, 7, 51, 28, 0, time.UTC), 32},
+		{time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC), 36},
+		{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 37},
+		{time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), 37},
+	}
+	for _, c := range cases {
+		if got := TAIMinusUTC(c.t); got != c.expected {
+			t.Errorf("%v: Expected %f; but got %f", c.t, c.expected, got)
+		}
+	}
+}
+
+func TestReadEOP(t *testing.T) {
+	eop, err := ReadEOP(strings.NewReader(finalsSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eop.records) != 2 {
+		t.Fatalf("Expected 2 records; but got %d", len(eop.records))
+	}
+
+	t.Run("Daily value", func(t *testing.T) {
+		rec, ok := eop.At(time.Date(2004, 4, 6, 0, 0, 0, 0, time.UTC))
+		if !ok || rec.XP != -0.140682 || rec.YP != 0.333309 || rec.LOD != 1.5563 || math.Abs(rec.UT1MinusUTC+0.4399619) > 1e-12 {
+			t.Errorf("Unexpected record %+v", rec)
+		}
+	})
+	t.Run("Interpolated", func(t *testing.T) {
+		rec, ok := eop.At(time.Date(2004, 4, 6, 12, 0, 0, 0, time.UTC))
+		if !ok || math.Abs(rec.UT1MinusUTC+0.4404619) > 1e-9 || math.Abs(rec.XP+0.140487) > 1e-9 {
+			t.Errorf("Unexpected record %+v", rec)
+		}
+	})
+	t.Run("Outside", func(t *testing.T) {
+		if _, ok := eop.At(time.Date(2004, 4, 7, 0, 0, 1, 0, time.UTC)); ok {
+			t.Error("Expected no record after the table")
+		}
+		if _, ok := eop.At(time.Date(2004, 4, 5, 23, 59, 59, 0, time.UTC)); ok {
+			t.Error("Expected no record before the table")
+		}
+	})
+	t.Run("Leap second", func(t *testing.T) {
+		leap, err := ReadEOP(strings.NewReader(
+			"161231 57753.00 I  0.100000 0.000100  0.300000 0.000100  I-0.4000000 0.0000100  1.0000 0.0100\n" +
+				"17 1 1 57754.00 I  0.100000 0.000100  0.300000 0.000100  I 0.6000000 0.0000100  1.0000 0.0100\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// UT1 runs smoothly across the leap second, so UT1 - UTC must not be averaged into +0.1 s
+		rec, _ := leap.At(time.Date(2016, 12, 31, 12, 0, 0, 0, time.UTC))
+		if math.Abs(rec.UT1MinusUTC+0.4) > 1e-9 {
+			t.Errorf("Expected %f; but got %f", -0.4, rec.UT1MinusUTC)
+		}
+	})
+}
+
+func TestTimeScales(t *testing.T) {
+	eop, err := ReadEOP(strings.NewReader(finalsSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := time.Date(2004, 4, 6, 7, 51, 28, 386009000, time.UTC)
+	ts := NewTimeScales(utc, eop)
+
+	t.Run("Offsets", func(t *testing.T) {
+		tt := (ts.TT.Day - ts.UTC.Day + ts.TT.Frac - ts.UTC.Frac) * 86400
+		if math.Abs(tt-64.184) > 1e-6 {
+			t.Errorf("Expected %f; but got %f", 64.184, tt)
+		}
+		tai := (ts.TAI.JD() - ts.UTC.JD()) * 86400
+		if math.Abs(tai-32) > 1e-4 {
+			t.Errorf("Expected %f; but got %f", 32.0, tai)
+		}
+	})
+	t.Run("Without EOP", func(t *testing.T) {
+		plain := NewTimeScales(utc, nil)
+		if plain.UT1 != plain.UTC || plain.XP != 0 || plain.YP != 0 {
+			t.Errorf("Expected UT1 = UTC and no polar motion; but got %+v", plain)
+		}
+	})
+	t.Run("TEME To ITRF", func(t *testing.T) {
+		// Vallado example 3-15, see TestFrames
+		r := TEMEVector{X: 5094.18016210, Y: 6127.64465950, Z: 6380.34453270}
+		v := TEMEVector{X: -4.746131487, Y: 0.785818041, Z: 5.531931288}
+		ritrf, _ := TEMEToITRFAt(r, v, ts)
+		expected := Vector3{X: -1033.4793830, Y: 7901.2952754, Z: 6380.3565958}
+		if math.Abs(ritrf.X-expected.X) > 1e-3 || math.Abs(ritrf.Y-expected.Y) > 1e-3 || math.Abs(ritrf.Z-expected.Z) > 1e-3 {
+			t.Errorf("Expected %v; but got %v", expected, ritrf)
+		}
+	})
+}
+
+func TestSiderealTime(t *testing.T) {
+	t.Run("ERA at J2000", func(t *testing.T) {
+		expected := TWOPI * 0.7790572732640
+		if got := ERA(2451545.0); math.Abs(got-expected) > 1e-12 {
+			t.Errorf("Expected %f; but got %f", expected, got)
+		}
+	})
+	t.Run("ThetaG_JD", func(t *testing.T) {
+		jd := 2453101.827411
+		if GMST(jd) != ThetaG_JD(jd) {
+			t.Errorf("Expected %f; but got %f", GMST(jd), ThetaG_JD(jd))
+		}
+	})
+	t.Run("GMST and ERA", func(t *testing.T) {
+		// both angles track the same rotation, they differ by the accumulated precession (~0.7 deg over 4 years)
+		jd := 2453101.827411
+		diff := math.Mod(GMST(jd)-ERA(jd)+3*math.Pi, TWOPI) - math.Pi
+		if math.Abs(diff) > 1.0*DEG2RAD {
+			t.Errorf("Expected less than 1 degree; but got %f", diff*RAD2DEG)
+		}
+	})
+}
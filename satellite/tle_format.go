@@ -0,0 +1,280 @@
+package satellite
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Holds the mean elements and identifiers of a two line element set, in TLE units (degrees, revs per day)
+type TLEElements struct {
+	SatNum         int64
+	Classification string
+	IntlDesignator string // launch year, launch number and piece, e.g. "98067A"
+	EpochYear      int64  // full four digit year
+	EpochDays      float64
+	NDot           float64 // first derivative of mean motion divided by two, revs per day^2
+	NDDot          float64 // second derivative of mean motion divided by six, revs per day^3
+	BStar          float64 // drag term, inverse earth radii
+	ElementNum     int64
+	Inclination    float64
+	RAAN           float64
+	Eccentricity   float64
+	ArgPerigee     float64
+	MeanAnomaly    float64
+	MeanMotion     float64
+	RevNum         int64
+}
+
+// Holds an Orbit Mean-Elements Message, field names and JSON keys follow the CCSDS OMM / CelesTrak conventions
+type OMM struct {
+	ObjectName         string  `json:"OBJECT_NAME"`
+	ObjectID           string  `json:"OBJECT_ID"`
+	Epoch              string  `json:"EPOCH"`
+	MeanMotion         float64 `json:"MEAN_MOTION"`
+	Eccentricity       float64 `json:"ECCENTRICITY"`
+	Inclination        float64 `json:"INCLINATION"`
+	RAOfAscNode        float64 `json:"RA_OF_ASC_NODE"`
+	ArgOfPericenter    float64 `json:"ARG_OF_PERICENTER"`
+	MeanAnomaly        float64 `json:"MEAN_ANOMALY"`
+	EphemerisType      int     `json:"EPHEMERIS_TYPE"`
+	ClassificationType string  `json:"CLASSIFICATION_TYPE"`
+	NoradCatID         int64   `json:"NORAD_CAT_ID"`
+	ElementSetNo       int64   `json:"ELEMENT_SET_NO"`
+	RevAtEpoch         int64   `json:"REV_AT_EPOCH"`
+	BStar              float64 `json:"BSTAR"`
+	MeanMotionDot      float64 `json:"MEAN_MOTION_DOT"`
+	MeanMotionDDot     float64 `json:"MEAN_MOTION_DDOT"`
+}
+
+// Recovers the TLE mean elements of a satellite initialized by TLEToSat, converting back from the internal sgp4 units
+func SatToElements(sat Satellite) TLEElements {
+	return TLEElements{
+		SatNum:         sat.satnum,
+		Classification: sat.classification,
+		IntlDesignator: sat.intldesg,
+		EpochYear:      epochYear(sat.epochyr),
+		EpochDays:      sat.epochdays,
+		NDot:           sat.ndot * XPDOTP * 1440.0,
+		NDDot:          sat.nddot * XPDOTP * 1440.0 * 1440.0,
+		BStar:          sat.bstar,
+		ElementNum:     sat.elnum,
+		Inclination:    sat.inclo * RAD2DEG,
+		RAAN:           sat.nodeo * RAD2DEG,
+		Eccentricity:   sat.ecco,
+		ArgPerigee:     sat.argpo * RAD2DEG,
+		MeanAnomaly:    sat.mo * RAD2DEG,
+		MeanMotion:     sat.noKozai * XPDOTP,
+		RevNum:         sat.revnum,
+	}
+}
+
+// Formats a satellite initialized by TLEToSat back into two line element format
+func SatToTLE(sat Satellite) (line1, line2 string, err error) {
+	return ElementsToTLE(SatToElements(sat))
+}
+
+// Formats mean elements into fixed column two line element format, including checksums
+func ElementsToTLE(el TLEElements) (line1, line2 string, err error) {
+	if el.SatNum < 0 || el.SatNum > 99999 {
+		return "", "", fmt.Errorf("satellite number %d does not fit in a TLE", el.SatNum)
+	}
+	if el.Eccentricity < 0 || el.Eccentricity >= 1 {
+		return "", "", fmt.Errorf("eccentricity %f not within range 0.0 <= e < 1.0", el.Eccentricity)
+	}
+	if el.Inclination < 0 || el.Inclination > 180 {
+		return "", "", fmt.Errorf("inclination %f not within range 0 to 180 degrees", el.Inclination)
+	}
+	if el.MeanMotion <= 0 || el.MeanMotion >= 100 {
+		return "", "", fmt.Errorf("mean motion %f revs/day does not fit in a TLE", el.MeanMotion)
+	}
+
+	ndot, err := formatTLEDecimal(el.NDot)
+	if err != nil {
+		return "", "", err
+	}
+	nddot, err := formatTLEExp(el.NDDot)
+	if err != nil {
+		return "", "", err
+	}
+	bstar, err := formatTLEExp(el.BStar)
+	if err != nil {
+		return "", "", err
+	}
+
+	class := el.Classification
+	if class == "" {
+		class = "U"
+	}
+
+	ecc := int64(math.Round(el.Eccentricity * 1e7))
+	if ecc > 9999999 {
+		ecc = 9999999
+	}
+
+	line1 = fmt.Sprintf("1 %05d%.1s %-8.8s %02d%012.8f %s %s %s 0 %4d",
+		el.SatNum, class, el.IntlDesignator, el.EpochYear%100, el.EpochDays, ndot, nddot, bstar, el.ElementNum%10000)
+	line2 = fmt.Sprintf("2 %05d %8.4f %8.4f %07d %8.4f %8.4f %11.8f%5d",
+		el.SatNum, el.Inclination, wrapDegrees(el.RAAN), ecc, wrapDegrees(el.ArgPerigee), wrapDegrees(el.MeanAnomaly), el.MeanMotion, el.RevNum%100000)
+
+	line1 += strconv.Itoa(tleChecksum(line1))
+	line2 += strconv.Itoa(tleChecksum(line2))
+	return
+}
+
+// Writes a satellite as a three line element record (name line followed by the two TLE lines)
+func WriteTLE(w io.Writer, name string, el TLEElements) error {
+	line1, line2, err := ElementsToTLE(el)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%-24s\n%s\n%s\n", name, line1, line2)
+	return err
+}
+
+// Builds an Orbit Mean-Elements Message from TLE mean elements
+func ElementsToOMM(name string, el TLEElements) OMM {
+	class := el.Classification
+	if class == "" {
+		class = "U"
+	}
+	return OMM{
+		ObjectName:         strings.TrimSpace(name),
+		ObjectID:           intlDesignatorToObjectID(el.IntlDesignator),
+		Epoch:              epochTime(el.EpochYear, el.EpochDays).Format("2006-01-02T15:04:05.000000"),
+		MeanMotion:         el.MeanMotion,
+		Eccentricity:       el.Eccentricity,
+		Inclination:        el.Inclination,
+		RAOfAscNode:        el.RAAN,
+		ArgOfPericenter:    el.ArgPerigee,
+		MeanAnomaly:        el.MeanAnomaly,
+		EphemerisType:      0,
+		ClassificationType: class,
+		NoradCatID:         el.SatNum,
+		ElementSetNo:       el.ElementNum,
+		RevAtEpoch:         el.RevNum,
+		BStar:              el.BStar,
+		MeanMotionDot:      el.NDot,
+		MeanMotionDDot:     el.NDDot,
+	}
+}
+
+// Formats an OMM as CCSDS keyword = value notation (KVN)
+func FormatOMM(o OMM) string {
+	var b strings.Builder
+	kv := func(k string, v interface{}) {
+		fmt.Fprintf(&b, "%-19s = %v\n", k, v)
+	}
+	kv("CCSDS_OMM_VERS", "2.0")
+	kv("OBJECT_NAME", o.ObjectName)
+	kv("OBJECT_ID", o.ObjectID)
+	kv("CENTER_NAME", "EARTH")
+	kv("REF_FRAME", "TEME")
+	kv("TIME_SYSTEM", "UTC")
+	kv("MEAN_ELEMENT_THEORY", "SGP4")
+	kv("EPOCH", o.Epoch)
+	kv("MEAN_MOTION", strconv.FormatFloat(o.MeanMotion, 'f', 8, 64))
+	kv("ECCENTRICITY", strconv.FormatFloat(o.Eccentricity, 'f', 7, 64))
+	kv("INCLINATION", strconv.FormatFloat(o.Inclination, 'f', 4, 64))
+	kv("RA_OF_ASC_NODE", strconv.FormatFloat(o.RAOfAscNode, 'f', 4, 64))
+	kv("ARG_OF_PERICENTER", strconv.FormatFloat(o.ArgOfPericenter, 'f', 4, 64))
+	kv("MEAN_ANOMALY", strconv.FormatFloat(o.MeanAnomaly, 'f', 4, 64))
+	kv("EPHEMERIS_TYPE", o.EphemerisType)
+	kv("CLASSIFICATION_TYPE", o.ClassificationType)
+	kv("NORAD_CAT_ID", o.NoradCatID)
+	kv("ELEMENT_SET_NO", o.ElementSetNo)
+	kv("REV_AT_EPOCH", o.RevAtEpoch)
+	kv("BSTAR", strconv.FormatFloat(o.BStar, 'g', -1, 64))
+	kv("MEAN_MOTION_DOT", strconv.FormatFloat(o.MeanMotionDot, 'g', -1, 64))
+	kv("MEAN_MOTION_DDOT", strconv.FormatFloat(o.MeanMotionDDot, 'g', -1, 64))
+	return b.String()
+}
+
+// Computes the TLE modulo 10 checksum: the sum of all digits, with minus signs counting as 1
+func tleChecksum(line string) int {
+	sum := 0
+	for i, c := range line {
+		if i >= 68 {
+			break
+		}
+		if c >= '0' && c <= '9' {
+			sum += int(c - '0')
+		} else if c == '-' {
+			sum++
+		}
+	}
+	return sum % 10
+}
+
+// Formats a value as the 10 column signed decimal used for ndot, e.g. " .00000408" or "-.00002182"
+func formatTLEDecimal(v float64) (string, error) {
+	s := strconv.FormatFloat(math.Abs(v), 'f', 8, 64)
+	if !strings.HasPrefix(s, "0.") {
+		return "", fmt.Errorf("value %g does not fit in a TLE decimal field", v)
+	}
+	if v < 0 && s != "0.00000000" {
+		return "-" + s[1:], nil
+	}
+	return " " + s[1:], nil
+}
+
+// Formats a value in the 8 column assumed decimal point exponent notation used for nddot and bstar, e.g. " 42495-3"
+func formatTLEExp(v float64) (string, error) {
+	if v == 0 {
+		return " 00000+0", nil
+	}
+	sign := " "
+	if v < 0 {
+		sign = "-"
+	}
+	a := math.Abs(v)
+	exp := int(math.Floor(math.Log10(a))) + 1
+	mant := int64(math.Round(a / math.Pow(10, float64(exp)) * 1e5))
+	if mant >= 100000 {
+		mant /= 10
+		exp++
+	}
+	if exp < -9 {
+		// too small to represent, round to zero
+		return " 00000+0", nil
+	}
+	if exp > 9 {
+		return "", fmt.Errorf("value %g does not fit in a TLE exponent field", v)
+	}
+	expSign := "+"
+	if exp < 0 {
+		expSign = "-"
+	}
+	return fmt.Sprintf("%s%05d%s%d", sign, mant, expSign, absInt(exp)), nil
+}
+
+// Converts a TLE international designator ("98067A") into the OMM object id form ("1998-067A")
+func intlDesignatorToObjectID(intl string) string {
+	intl = strings.TrimSpace(intl)
+	if len(intl) < 5 {
+		return intl
+	}
+	yr, err := strconv.ParseInt(intl[0:2], 10, 0)
+	if err != nil {
+		return intl
+	}
+	return fmt.Sprintf("%d-%s", epochYear(yr), intl[2:])
+}
+
+// Wraps an angle in degrees into the range [0, 360)
+func wrapDegrees(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+func absInt(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,88 @@
+package satellite
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFormatTLE(t *testing.T) {
+	t.Run("Round Trip SatDB", func(t *testing.T) {
+		f, err := os.Open("SatDB.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		var lines []string
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		for i := 0; i+2 < len(lines); i += 3 {
+			sat := TLEToSat(lines[i+1], lines[i+2], GravityWGS72)
+			line1, line2, err := SatToTLE(sat)
+			if err != nil {
+				t.Fatalf("%s: %v", lines[i], err)
+			}
+			if line1 != lines[i+1] {
+				t.Errorf("Expected %q; but got %q", lines[i+1], line1)
+			}
+			if line2 != lines[i+2] {
+				t.Errorf("Expected %q; but got %q", lines[i+2], line2)
+			}
+		}
+	})
+	t.Run("Exponent Fields", func(t *testing.T) {
+		cases := map[float64]string{
+			0:          " 00000+0",
+			0.42495e-3: " 42495-3",
+			-1.1606e-5: "-11606-4",
+			0.13844e-3: " 13844-3",
+			0.999999:   " 10000+1",
+			1.5:        " 15000+1",
+		}
+		for v, expected := range cases {
+			got, err := formatTLEExp(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != expected {
+				t.Errorf("Expected %q; but got %q", expected, got)
+			}
+		}
+	})
+	t.Run("Checksum", func(t *testing.T) {
+		line := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+		if tleChecksum(line) != 7 {
+			t.Errorf("Expected %d; but got %d", 7, tleChecksum(line))
+		}
+	})
+}
+
+func TestFormatOMM(t *testing.T) {
+	sat := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", GravityWGS72)
+	omm := ElementsToOMM("ISS (ZARYA)", SatToElements(sat))
+
+	if omm.ObjectID != "1998-067A" {
+		t.Errorf("Expected %s; but got %s", "1998-067A", omm.ObjectID)
+	}
+	if omm.Epoch != "2008-09-20T12:25:40.104192" {
+		t.Errorf("Expected %s; but got %s", "2008-09-20T12:25:40.104192", omm.Epoch)
+	}
+
+	kvn := FormatOMM(omm)
+	if !strings.Contains(kvn, "MEAN_MOTION         = 15.72125391\n") {
+		t.Errorf("Unexpected KVN output:\n%s", kvn)
+	}
+
+	js, err := json.Marshal(omm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(js), `"NORAD_CAT_ID":25544`) {
+		t.Errorf("Unexpected JSON output: %s", js)
+	}
+}
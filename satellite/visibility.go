@@ -0,0 +1,35 @@
+package satellite
+
+import "math"
+
+// Decides whether two satellites can see each other. r1 and r2 are positions in km in the same Earth centered
+// frame (ECI or ECEF, the WGS84 ellipsoid is symmetric about the z axis). minAlt is the grazing altitude in km
+// below which the ray is blocked, e.g. 100 km to keep links out of the atmosphere.
+// Returns the visibility and the minimum altitude of the ray above the WGS84 ellipsoid, found by golden
+// section search along the segment to 1e-7 of its length.
+func LineOfSight(r1, r2 Vector3, minAlt float64) (visible bool, minRayAlt float64) {
+	altitude := func(s float64) float64 {
+		p := Vector3{X: r1.X + s*(r2.X-r1.X), Y: r1.Y + s*(r2.Y-r1.Y), Z: r1.Z + s*(r2.Z-r1.Z)}
+		return ECEFToGeodetic(p).Altitude
+	}
+
+	// the altitude along a straight line has a single minimum, which may be at either end
+	invPhi := (math.Sqrt(5) - 1) / 2
+	a, b := 0.0, 1.0
+	c := b - invPhi*(b-a)
+	d := a + invPhi*(b-a)
+	fc, fd := altitude(c), altitude(d)
+	for b-a > 1e-7 {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - invPhi*(b-a)
+			fc = altitude(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + invPhi*(b-a)
+			fd = altitude(d)
+		}
+	}
+	minRayAlt = math.Min(altitude((a+b)/2), math.Min(altitude(0), altitude(1)))
+	return minRayAlt >= minAlt, minRayAlt
+}
@@ -0,0 +1,34 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLineOfSight(t *testing.T) {
+	cases := []struct {
+		name        string
+		r1, r2      Vector3
+		minAlt      float64
+		visible     bool
+		expectedAlt float64
+	}{
+		{"Equatorial", Vector3{X: 7000, Y: 1000}, Vector3{X: 7000, Y: -1000}, 100, true, 7000 - wgs84A},
+		{"Polar", Vector3{X: 1000, Z: 7000}, Vector3{X: -1000, Z: 7000}, 100, true, 7000 - wgs84B},
+		{"Grazing", Vector3{X: 7000, Y: 1000}, Vector3{X: 7000, Y: -1000}, 700, false, 7000 - wgs84A},
+		{"Occluded", Vector3{X: 7000}, Vector3{Y: 7000}, 0, false, 7000/math.Sqrt2 - wgs84A},
+		{"Opposite", Vector3{X: 7000}, Vector3{X: -7000}, 0, false, -wgs84A},
+		{"Endpoint", Vector3{X: 6500}, Vector3{X: 8000, Y: 500}, 0, true, 6500 - wgs84A},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			visible, alt := LineOfSight(c.r1, c.r2, c.minAlt)
+			if visible != c.visible {
+				t.Errorf("Expected %t; but got %t", c.visible, visible)
+			}
+			if math.Abs(alt-c.expectedAlt) > 1e-3 {
+				t.Errorf("Expected %f; but got %f", c.expectedAlt, alt)
+			}
+		})
+	}
+}
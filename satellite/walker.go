@@ -0,0 +1,195 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Arrangement of the orbital planes of a Walker constellation
+type WalkerPattern int
+
+const (
+	WalkerDelta WalkerPattern = iota // planes spread over 360 degrees of RAAN
+	WalkerStar                       // planes spread over 180 degrees of RAAN, e.g. polar constellations
+)
+
+func (p WalkerPattern) String() string {
+	if p == WalkerStar {
+		return "star"
+	}
+	return "delta"
+}
+
+// A Walker constellation i:T/P/F of circular orbits: T satellites evenly spread over P planes of inclination i,
+// with satellites of adjacent planes phased by F*360/T degrees of argument of latitude.
+// Angles in degrees, as in TLEElements.
+type WalkerConstellation struct {
+	Pattern     WalkerPattern
+	Inclination float64 // degrees
+	Total       int     // T, number of satellites
+	Planes      int     // P, number of equally spaced planes, must divide T
+	Phasing     int     // F, relative phasing between adjacent planes, within [0, P)
+	Altitude    float64 // km above the equatorial radius of WGS84
+	RAAN        float64 // RAAN of the first plane (degrees)
+	Epoch       time.Time
+	Name        string // name prefix of the satellites, defaults to "WALKER"
+	FirstSatNum int64  // catalog number of the first satellite, defaults to 90000
+}
+
+// Parses the Walker notation "i:T/P/F" (e.g. "53:1584/72/17") into a delta constellation. Altitude, RAAN and
+// Epoch are left zero.
+func ParseWalker(notation string) (WalkerConstellation, error) {
+	w := WalkerConstellation{Pattern: WalkerDelta}
+	incl, tpf, ok := strings.Cut(notation, ":")
+	fields := strings.Split(tpf, "/")
+	if !ok || len(fields) != 3 {
+		return w, fmt.Errorf("walker notation %q is not i:T/P/F", notation)
+	}
+	var err error
+	if w.Inclination, err = strconv.ParseFloat(strings.TrimSpace(incl), 64); err != nil {
+		return w, fmt.Errorf("walker notation %q: %w", notation, err)
+	}
+	for i, dst := range []*int{&w.Total, &w.Planes, &w.Phasing} {
+		if *dst, err = strconv.Atoi(strings.TrimSpace(fields[i])); err != nil {
+			return w, fmt.Errorf("walker notation %q: %w", notation, err)
+		}
+	}
+	return w, w.validate(false)
+}
+
+// Returns the mean elements of every satellite, plane by plane, as circular Kepler elements (radians, km). The
+// argument of latitude is held in TrueAnomaly, see KeplerElements.
+func (w WalkerConstellation) Elements() ([]KeplerElements, error) {
+	if err := w.validate(true); err != nil {
+		return nil, err
+	}
+	perPlane := w.Total / w.Planes
+	spread := 360.0
+	if w.Pattern == WalkerStar {
+		spread = 180.0
+	}
+
+	elements := make([]KeplerElements, 0, w.Total)
+	for p := 0; p < w.Planes; p++ {
+		raan := w.RAAN + float64(p)*spread/float64(w.Planes)
+		for s := 0; s < perPlane; s++ {
+			u := float64(s)*360.0/float64(perPlane) + float64(p*w.Phasing)*360.0/float64(w.Total)
+			elements = append(elements, KeplerElements{
+				SemiMajorAxis: getGravConst(GravityWGS84).radiusearthkm + w.Altitude,
+				Inclination:   w.Inclination * DEG2RAD,
+				RAAN:          wrapDegrees(raan) * DEG2RAD,
+				TrueAnomaly:   wrapDegrees(u) * DEG2RAD,
+			})
+		}
+	}
+	return elements, nil
+}
+
+// Generates the constellation as a catalog of synthetic TLEs, propagated by sgp4 like any catalog satellite.
+// The mean motion is chosen so that the sgp4 mean semi-major axis matches the altitude. Drag terms are zero.
+func (w WalkerConstellation) Catalog() (Catalog, error) {
+	elements, err := w.Elements()
+	if err != nil {
+		return nil, err
+	}
+	if w.firstSatNum()+int64(w.Total)-1 > 99999 {
+		return nil, fmt.Errorf("walker constellation of %d satellites from %d does not fit TLE catalog numbers", w.Total, w.firstSatNum())
+	}
+	year, days := timeToEpoch(w.Epoch)
+	grav := getGravConst(GravityWGS84)
+	cat := make(Catalog, len(elements))
+	for k, el := range elements {
+		tle := TLEElements{
+			SatNum:      w.firstSatNum() + int64(k),
+			EpochYear:   year,
+			EpochDays:   days,
+			ElementNum:  999,
+			Inclination: el.Inclination * RAD2DEG,
+			RAAN:        el.RAAN * RAD2DEG,
+			MeanAnomaly: el.TrueAnomaly * RAD2DEG,
+			MeanMotion:  kozaiMeanMotion(el.SemiMajorAxis/grav.radiusearthkm, el.Inclination, grav) * XPDOTP,
+		}
+		line1, line2, err := ElementsToTLE(tle)
+		if err != nil {
+			return nil, err
+		}
+		cat[k] = SimpleSatellite{Name: w.satName(k), Ole1: line1, Ole2: line2}
+	}
+	return cat, nil
+}
+
+// Generates the constellation as a catalog of satellites driven by NumericalPropagators started from the
+// osculating circular elements at the epoch
+func (w WalkerConstellation) NumericalCatalog(opts NumericalOptions) (Catalog, error) {
+	elements, err := w.Elements()
+	if err != nil {
+		return nil, err
+	}
+	cat := make(Catalog, len(elements))
+	for k, el := range elements {
+		prop, err := NewNumericalPropagatorFromKepler(w.Epoch, el, opts)
+		if err != nil {
+			return nil, err
+		}
+		cat[k] = NewSimpleSatellite(w.satName(k), prop)
+	}
+	return cat, nil
+}
+
+func (w WalkerConstellation) validate(orbit bool) error {
+	switch {
+	case w.Total <= 0 || w.Planes <= 0:
+		return errors.New("walker constellation needs at least one satellite and one plane")
+	case w.Total%w.Planes != 0:
+		return fmt.Errorf("walker constellation of %d satellites cannot be split into %d planes", w.Total, w.Planes)
+	case w.Phasing < 0 || w.Phasing >= w.Planes:
+		return fmt.Errorf("walker phasing %d not within range 0 to %d", w.Phasing, w.Planes-1)
+	case w.Inclination < 0 || w.Inclination > 180:
+		return fmt.Errorf("inclination %f not within range 0 to 180 degrees", w.Inclination)
+	case orbit && w.Altitude <= 0:
+		return fmt.Errorf("walker altitude %f km is not above the Earth", w.Altitude)
+	}
+	return nil
+}
+
+func (w WalkerConstellation) firstSatNum() int64 {
+	if w.FirstSatNum > 0 {
+		return w.FirstSatNum
+	}
+	return 90000
+}
+
+// Names satellite k as "<name> <plane>-<slot>", both counted from 1
+func (w WalkerConstellation) satName(k int) string {
+	name := w.Name
+	if name == "" {
+		name = "WALKER"
+	}
+	perPlane := w.Total / w.Planes
+	return fmt.Sprintf("%s %02d-%02d", name, k/perPlane+1, k%perPlane+1)
+}
+
+// Returns the Kozai mean motion (radians per minute) a TLE must carry for sgp4 to recover the mean semi-major
+// axis ao (earth radii) at the inclination, inverting the un-Kozai step of initl by fixed point iteration
+func kozaiMeanMotion(ao, inclo float64, grav GravConst) float64 {
+	no := grav.xke / math.Pow(ao, 1.5)
+	cosio := math.Cos(inclo)
+	d1 := 0.75 * grav.j2 * (3.0*cosio*cosio - 1.0)
+	noKozai := no
+	for i := 0; i < 20; i++ {
+		ak := math.Pow(grav.xke/noKozai, 2.0/3.0)
+		del := d1 / (ak * ak)
+		adel := ak * (1.0 - del*del - del*(1.0/3.0+134.0*del*del/81.0))
+		del = d1 / (adel * adel)
+		next := no * (1.0 + del)
+		if math.Abs(next-noKozai) < 1e-15 {
+			return next
+		}
+		noKozai = next
+	}
+	return noKozai
+}
@@ -0,0 +1,93 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseWalker(t *testing.T) {
+	w, err := ParseWalker("53:1584/72/17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Inclination != 53 || w.Total != 1584 || w.Planes != 72 || w.Phasing != 17 {
+		t.Errorf("Unexpected constellation %+v", w)
+	}
+	for _, notation := range []string{"53:1584/72", "53-1584/72/17", "53:10/3/0", "53:12/3/3", "x:12/3/1"} {
+		t.Run(notation, func(t *testing.T) {
+			if _, err := ParseWalker(notation); err == nil {
+				t.Errorf("Expected an error for %q", notation)
+			}
+		})
+	}
+}
+
+func TestWalkerConstellation(t *testing.T) {
+	epoch := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	w := WalkerConstellation{Inclination: 53, Total: 24, Planes: 6, Phasing: 1, Altitude: 550, RAAN: 10, Epoch: epoch}
+
+	t.Run("Elements", func(t *testing.T) {
+		elements, err := w.Elements()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// first satellite of the second plane
+		el := elements[4]
+		if math.Abs(el.RAAN*RAD2DEG-70) > 1e-9 || math.Abs(el.TrueAnomaly*RAD2DEG-15) > 1e-9 {
+			t.Errorf("Expected RAAN 70 and argument of latitude 15; but got %f, %f", el.RAAN*RAD2DEG, el.TrueAnomaly*RAD2DEG)
+		}
+		star := w
+		star.Pattern = WalkerStar
+		elements, _ = star.Elements()
+		if raan := elements[4].RAAN * RAD2DEG; math.Abs(raan-40) > 1e-9 {
+			t.Errorf("Expected %f; but got %f", 40.0, raan)
+		}
+	})
+
+	t.Run("Catalog", func(t *testing.T) {
+		cat, err := w.Catalog()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cat) != 24 || cat[5].Name != "WALKER 02-02" {
+			t.Fatalf("Unexpected catalog %v", cat)
+		}
+		for i := range cat {
+			orbit, err := cat[i].Orbit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(orbit.ApogeeAltitude-550) > 1e-3 || math.Abs(orbit.PerigeeAltitude-550) > 1e-3 || math.Abs(orbit.Inclination-53) > 1e-4 {
+				t.Errorf("Expected a 550 km orbit at 53 degrees; but got %+v", orbit)
+			}
+			if orbit.NoradID != 90000+int64(i) || math.Abs(orbit.Epoch.Sub(epoch).Seconds()) > 1e-3 {
+				t.Errorf("Unexpected id %d or epoch %v", orbit.NoradID, orbit.Epoch)
+			}
+		}
+	})
+
+	t.Run("Numerical", func(t *testing.T) {
+		numerical, err := w.NumericalCatalog(NumericalOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cat, _ := w.Catalog()
+		for i := range numerical {
+			if err := numerical[i].PropagateTo(epoch); err != nil {
+				t.Fatal(err)
+			}
+			if err := cat[i].PropagateTo(epoch); err != nil {
+				t.Fatal(err)
+			}
+			if r := vecNorm(numerical[i].Position); math.Abs(r-6928.137) > 1e-6 {
+				t.Errorf("Expected %f; but got %f", 6928.137, r)
+			}
+			// osculating sgp4 positions differ from the mean orbit by the short periodic terms
+			p, q := numerical[i].Position, cat[i].Position
+			if d := vecNorm(Vector3{X: p.X - q.X, Y: p.Y - q.Y, Z: p.Z - q.Z}); d > 30 {
+				t.Errorf("Expected the sgp4 and numerical positions within 30 km; but got %f", d)
+			}
+		}
+	})
+}